@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+
+	"gochess/pkg/evaluation"
+	"gochess/pkg/notation/position"
+
+	"github.com/spf13/cobra"
+)
+
+var evalFlags struct {
+	fen string
+}
+
+var evalCmd = &cobra.Command{
+	Use:   "eval",
+	Short: "Break the static evaluation of a position down by component",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := position.NewPosition(position.FEN(evalFlags.fen))
+		if err != nil {
+			return fmt.Errorf("invalid fen: %w", err)
+		}
+		fmt.Print(evaluation.Trace(p))
+		return nil
+	},
+}
+
+func init() {
+	evalCmd.Flags().StringVar(&evalFlags.fen, "fen", string(position.StartingFEN), "position to evaluate")
+	rootCmd.AddCommand(evalCmd)
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
-	"gochess/pkg/game"
+	"os"
 )
 
 func main() {
-	game.GameLoop()
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"gochess/pkg/generation"
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/position"
+
+	"github.com/spf13/cobra"
+)
+
+var perftFlags struct {
+	fen    string
+	depth  int
+	divide bool
+}
+
+var perftCmd = &cobra.Command{
+	Use:   "perft",
+	Short: "Count the leaf nodes of the legal move tree",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := position.NewPosition(position.FEN(perftFlags.fen))
+		if err != nil {
+			return fmt.Errorf("invalid fen: %w", err)
+		}
+
+		if !perftFlags.divide {
+			fmt.Printf("Nodes searched: %d\n", generation.Perft(p, perftFlags.depth))
+			return nil
+		}
+
+		// Print each root move in the same form as reference engines
+		divide := generation.PerftDivide(p, perftFlags.depth)
+		moves := make([]move.PCN, 0, len(divide))
+		for m := range divide {
+			moves = append(moves, m)
+		}
+		slices.Sort(moves)
+
+		var total int
+		for _, m := range moves {
+			fmt.Printf("%s: %d\n", m, divide[m])
+			total += divide[m]
+		}
+		fmt.Printf("\nNodes searched: %d\n", total)
+		return nil
+	},
+}
+
+func init() {
+	perftCmd.Flags().StringVar(&perftFlags.fen, "fen", string(position.StartingFEN), "position to count from")
+	perftCmd.Flags().IntVarP(&perftFlags.depth, "depth", "d", 1, "depth in plies")
+	perftCmd.Flags().BoolVar(&perftFlags.divide, "divide", false, "break the count down per root move")
+	rootCmd.AddCommand(perftCmd)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"time"
+
+	"gochess/pkg/game"
+	"gochess/pkg/search"
+
+	"github.com/spf13/cobra"
+)
+
+var rootFlags struct {
+	human    string
+	depth    int
+	nodes    int
+	moveTime time.Duration
+}
+
+var rootCmd = &cobra.Command{
+	Use:   "gochess",
+	Short: "A chess engine written in Go",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		human, err := game.ParseSide(rootFlags.human)
+		if err != nil {
+			return err
+		}
+		game.GameLoop(game.Config{
+			Human:  human,
+			Limits: search.Limits{Depth: rootFlags.depth, Nodes: rootFlags.nodes, MoveTime: rootFlags.moveTime},
+		})
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.Flags().StringVar(&rootFlags.human, "human", "white", "color played by the human: white, black, both or none")
+	rootCmd.Flags().IntVarP(&rootFlags.depth, "depth", "d", 0, "depth in plies the engine searches")
+	rootCmd.Flags().IntVar(&rootFlags.nodes, "nodes", 0, "nodes the engine searches")
+	rootCmd.Flags().DurationVar(&rootFlags.moveTime, "movetime", 0, "time the engine thinks about each move (default 1s with no other limit)")
+}
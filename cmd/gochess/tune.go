@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"gochess/pkg/evaluation"
+	"gochess/pkg/tuning"
+
+	"github.com/spf13/cobra"
+)
+
+var tuneFlags struct {
+	data    string
+	params  string
+	out     string
+	passes  int
+	threads int
+}
+
+var tuneCmd = &cobra.Command{
+	Use:   "tune",
+	Short: "Fit the evaluation weights to labelled quiet positions",
+	Long: `Fit the evaluation weights to a file of quiet positions labelled with the
+results of their games, one FEN and result per line, with Texel's local search.
+
+The parameters are written after each pass, as Go source replacing
+pkg/evaluation/default_params.go when the output ends in .go, else as JSON
+that the engine loads with the EvalFile option.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Load the positions and the starting parameters
+		f, err := os.Open(tuneFlags.data)
+		if err != nil {
+			return err
+		}
+		entries, err := tuning.ReadEntries(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("invalid data %s: %w", tuneFlags.data, err)
+		}
+		params := evaluation.DefaultParams
+		if tuneFlags.params != "" {
+			loaded, err := evaluation.LoadParams(tuneFlags.params)
+			if err != nil {
+				return err
+			}
+			params = *loaded
+		}
+		fmt.Printf("Loaded %d positions\n", len(entries))
+
+		tuner := tuning.NewTuner(entries, params)
+		tuner.Threads = tuneFlags.threads
+		tuner.FindK()
+		fmt.Printf("K: %.4f, error: %.6f\n", tuner.K, tuner.Error())
+
+		// Save the progress after each pass
+		var saveErr error
+		tuner.OnPass = func(pass int, err float64) {
+			fmt.Printf("Pass %d: error %.6f\n", pass, err)
+			if saveErr == nil {
+				saveErr = writeParams(&tuner.Params, tuneFlags.out)
+			}
+		}
+		tuner.Tune(tuneFlags.passes)
+		if saveErr != nil {
+			return saveErr
+		}
+		fmt.Printf("Parameters written to %s\n", tuneFlags.out)
+		return nil
+	},
+}
+
+func writeParams(params *evaluation.Params, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if filepath.Ext(path) == ".go" {
+		err = params.WriteGo(f)
+	} else {
+		err = params.WriteJSON(f)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func init() {
+	tuneCmd.Flags().StringVar(&tuneFlags.data, "data", "", "file of labelled quiet positions")
+	tuneCmd.Flags().StringVar(&tuneFlags.params, "params", "", "JSON parameters to start from instead of the default ones")
+	tuneCmd.Flags().StringVarP(&tuneFlags.out, "out", "o", "params.json", "file to write the parameters to, Go source when ending in .go")
+	tuneCmd.Flags().IntVar(&tuneFlags.passes, "passes", 0, "maximum number of passes over the weights, 0 for no limit")
+	tuneCmd.Flags().IntVar(&tuneFlags.threads, "threads", runtime.NumCPU(), "number of threads computing the error")
+	_ = tuneCmd.MarkFlagRequired("data")
+	rootCmd.AddCommand(tuneCmd)
+}
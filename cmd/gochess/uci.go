@@ -0,0 +1,22 @@
+package main
+
+import (
+	"os"
+
+	"gochess/pkg/uci"
+
+	"github.com/spf13/cobra"
+)
+
+var uciCmd = &cobra.Command{
+	Use:   "uci",
+	Short: "Speak the Universal Chess Interface over stdin and stdout",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return uci.NewEngine(os.Stdin, os.Stdout).Run()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uciCmd)
+}
@@ -0,0 +1,86 @@
+package bitboard
+
+import (
+	"gochess/pkg/notation/square"
+)
+
+// direction is a step on the board in files and ranks.
+type direction struct{ df, dr int }
+
+var (
+	knightDirections = []direction{{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2}}
+	bishopDirections = []direction{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+	rookDirections   = []direction{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+	kingDirections   = append(append([]direction{}, bishopDirections...), rookDirections...)
+)
+
+// Precomputed attacks of the pieces that don't slide
+var (
+	KnightAttacks [64]Bitboard
+	KingAttacks   [64]Bitboard
+	// PawnAttacks is indexed by color, white first
+	PawnAttacks [2][64]Bitboard
+)
+
+// Precomputed squares between and through pairs of aligned squares
+var (
+	between [64][64]Bitboard
+	line    [64][64]Bitboard
+)
+
+func init() {
+	for s := square.Square_a1; s <= square.Square_h8; s++ {
+		KnightAttacks[s] = rayAttacks(s, Full, knightDirections)
+		KingAttacks[s] = rayAttacks(s, Full, kingDirections)
+		PawnAttacks[0][s] = rayAttacks(s, Full, []direction{{1, 1}, {-1, 1}})
+		PawnAttacks[1][s] = rayAttacks(s, Full, []direction{{1, -1}, {-1, -1}})
+	}
+
+	for s1 := square.Square_a1; s1 <= square.Square_h8; s1++ {
+		for _, d := range kingDirections {
+			ray := rayAttacks(s1, Empty, []direction{d})
+			opposite := rayAttacks(s1, Empty, []direction{{-d.df, -d.dr}})
+			for b := ray; b != 0; {
+				s2 := b.PopLSB()
+				between[s1][s2] = ray & rayAttacks(s2, Empty, []direction{{-d.df, -d.dr}})
+				line[s1][s2] = ray | opposite | New(s1)
+			}
+		}
+	}
+
+	initMagics()
+}
+
+// PawnAttack returns the squares attacked by a pawn of the given color on the square.
+func PawnAttack(isWhite bool, s square.Square) Bitboard {
+	if isWhite {
+		return PawnAttacks[0][s]
+	}
+	return PawnAttacks[1][s]
+}
+
+// Between returns the squares strictly between two squares on the same rank, file or diagonal.
+func Between(s1, s2 square.Square) Bitboard { return between[s1][s2] }
+
+// Line returns the whole rank, file or diagonal through two squares, or Empty if they aren't aligned.
+func Line(s1, s2 square.Square) Bitboard { return line[s1][s2] }
+
+// rayAttacks walks each direction from the square until it leaves the board or hits
+// an occupied square. Used to build the lookup tables.
+func rayAttacks(s square.Square, occupied Bitboard, directions []direction) Bitboard {
+	var attacks Bitboard
+	f, r := s.FileRank()
+	for _, d := range directions {
+		for i := 1; i <= 7; i++ {
+			to, err := square.NewSquareCheck(f+square.File(d.df*i), r+square.Rank(d.dr*i))
+			if err != nil {
+				break
+			}
+			attacks = attacks.Set(to)
+			if occupied.Has(to) {
+				break
+			}
+		}
+	}
+	return attacks
+}
@@ -0,0 +1,93 @@
+package bitboard
+
+import (
+	"math/bits"
+
+	"gochess/pkg/notation/square"
+)
+
+// Bitboard is a set of squares, bit n is set when square n is in the set.
+type Bitboard uint64
+
+const (
+	Empty Bitboard = 0
+	Full  Bitboard = 0xFFFFFFFFFFFFFFFF
+
+	FileA Bitboard = 0x0101010101010101
+	FileH Bitboard = FileA << 7
+	Rank1 Bitboard = 0xFF
+	Rank8 Bitboard = Rank1 << 56
+
+	LightSquares Bitboard = 0x55AA55AA55AA55AA
+	DarkSquares  Bitboard = ^LightSquares
+)
+
+func New(squares ...square.Square) Bitboard {
+	var b Bitboard
+	for _, s := range squares {
+		b = b.Set(s)
+	}
+	return b
+}
+
+func FileMask(f square.File) Bitboard { return FileA << uint(f) }
+func RankMask(r square.Rank) Bitboard { return Rank1 << (8 * uint(r)) }
+
+func (b Bitboard) Has(s square.Square) bool        { return b&(1<<uint(s)) != 0 }
+func (b Bitboard) Set(s square.Square) Bitboard    { return b | 1<<uint(s) }
+func (b Bitboard) Clear(s square.Square) Bitboard  { return b &^ (1 << uint(s)) }
+func (b Bitboard) Toggle(s square.Square) Bitboard { return b ^ 1<<uint(s) }
+
+func (b Bitboard) IsEmpty() bool { return b == 0 }
+func (b Bitboard) Count() int    { return bits.OnesCount64(uint64(b)) }
+
+// MoreThanOne reports if at least two squares are set.
+func (b Bitboard) MoreThanOne() bool { return b&(b-1) != 0 }
+
+// LSB returns the lowest square of the set, or Square_Invalid if the set is empty.
+func (b Bitboard) LSB() square.Square {
+	if b == 0 {
+		return square.Square_Invalid
+	}
+	return square.Square(bits.TrailingZeros64(uint64(b)))
+}
+
+// PopLSB removes the lowest square from the set and returns it.
+func (b *Bitboard) PopLSB() square.Square {
+	s := b.LSB()
+	*b &= *b - 1
+	return s
+}
+
+// Squares returns the squares of the set in ascending order.
+func (b Bitboard) Squares() []square.Square {
+	squares := make([]square.Square, 0, b.Count())
+	for b != 0 {
+		squares = append(squares, b.PopLSB())
+	}
+	return squares
+}
+
+// ==================== Shifts ====================
+
+func (b Bitboard) North() Bitboard { return b << 8 }
+func (b Bitboard) South() Bitboard { return b >> 8 }
+func (b Bitboard) East() Bitboard  { return (b &^ FileH) << 1 }
+func (b Bitboard) West() Bitboard  { return (b &^ FileA) >> 1 }
+
+// ==================== Ascii ====================
+
+func (b Bitboard) String() string {
+	var str string
+	for r := square.Rank8; r >= square.Rank1; r-- {
+		for f := square.FileA; f <= square.FileH; f++ {
+			if b.Has(square.NewSquare(f, r)) {
+				str += " X"
+			} else {
+				str += " ."
+			}
+		}
+		str += "\n"
+	}
+	return str
+}
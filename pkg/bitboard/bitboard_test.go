@@ -0,0 +1,96 @@
+package bitboard_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"gochess/pkg/bitboard"
+	"gochess/pkg/notation/square"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitboard(t *testing.T) {
+	b := bitboard.New(square.Square_a1, square.Square_e4, square.Square_h8)
+	assert.Equal(t, 3, b.Count())
+	assert.True(t, b.Has(square.Square_e4))
+	assert.False(t, b.Has(square.Square_e5))
+	assert.True(t, b.MoreThanOne())
+	assert.Equal(t, []square.Square{square.Square_a1, square.Square_e4, square.Square_h8}, b.Squares())
+
+	assert.Equal(t, square.Square_a1, b.PopLSB())
+	assert.Equal(t, square.Square_e4, b.LSB())
+	assert.Equal(t, square.Square_Invalid, bitboard.Empty.LSB())
+
+	assert.Equal(t, bitboard.New(square.Square_b1), bitboard.New(square.Square_a1).East())
+	assert.Equal(t, bitboard.Empty, bitboard.New(square.Square_h1).East())
+	assert.Equal(t, bitboard.Empty, bitboard.New(square.Square_a1).West())
+}
+
+func TestLeaperAttacks(t *testing.T) {
+	assert.Equal(t, bitboard.New(square.Square_b3, square.Square_c2), bitboard.KnightAttacks[square.Square_a1])
+	assert.Equal(t, 8, bitboard.KnightAttacks[square.Square_e4].Count())
+	assert.Equal(t, 3, bitboard.KingAttacks[square.Square_h8].Count())
+	assert.Equal(t, bitboard.New(square.Square_d3, square.Square_f3), bitboard.PawnAttack(true, square.Square_e2))
+	assert.Equal(t, bitboard.New(square.Square_g6), bitboard.PawnAttack(false, square.Square_h7))
+}
+
+func TestBetweenAndLine(t *testing.T) {
+	assert.Equal(t, bitboard.New(square.Square_c3, square.Square_d4), bitboard.Between(square.Square_b2, square.Square_e5))
+	assert.Equal(t, bitboard.New(square.Square_f3, square.Square_g2), bitboard.Between(square.Square_h1, square.Square_e4))
+	assert.Equal(t, bitboard.Empty, bitboard.Between(square.Square_a1, square.Square_b3))
+	assert.Equal(t, bitboard.Empty, bitboard.Between(square.Square_a1, square.Square_a2))
+
+	assert.Equal(t, bitboard.FileMask(square.FileE), bitboard.Line(square.Square_e2, square.Square_e7))
+	assert.Equal(t, bitboard.Empty, bitboard.Line(square.Square_a1, square.Square_b3))
+}
+
+func TestSlidingAttacks(t *testing.T) {
+	occupied := bitboard.New(square.Square_e6, square.Square_c4, square.Square_g2)
+	assert.Equal(t, bitboard.New(
+		square.Square_e1, square.Square_e2, square.Square_e3, square.Square_e5, square.Square_e6,
+		square.Square_c4, square.Square_d4,
+		square.Square_f4, square.Square_g4, square.Square_h4,
+	), bitboard.RookAttacks(square.Square_e4, occupied))
+	assert.Equal(t, bitboard.New(
+		square.Square_d5, square.Square_c6, square.Square_b7, square.Square_a8,
+		square.Square_f5, square.Square_g6, square.Square_h7,
+		square.Square_d3, square.Square_c2, square.Square_b1,
+		square.Square_f3, square.Square_g2,
+	), bitboard.BishopAttacks(square.Square_e4, occupied))
+
+	// Compare against a plain ray walk for random occupancies
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		occupied := bitboard.Bitboard(rng.Uint64() & rng.Uint64())
+		for s := square.Square_a1; s <= square.Square_h8; s++ {
+			assert.Equal(t, slowAttacks(s, occupied, 1, 0)|slowAttacks(s, occupied, 0, 1), bitboard.RookAttacks(s, occupied))
+			assert.Equal(t, slowAttacks(s, occupied, 1, 1)|slowAttacks(s, occupied, 1, -1), bitboard.BishopAttacks(s, occupied))
+		}
+	}
+}
+
+func slowAttacks(s square.Square, occupied bitboard.Bitboard, df, dr int) bitboard.Bitboard {
+	var attacks bitboard.Bitboard
+	f, r := s.FileRank()
+	for _, sign := range []int{1, -1} {
+		for i := 1; i <= 7; i++ {
+			to, err := square.NewSquareCheck(f+square.File(df*sign*i), r+square.Rank(dr*sign*i))
+			if err != nil {
+				break
+			}
+			attacks = attacks.Set(to)
+			if occupied.Has(to) {
+				break
+			}
+		}
+	}
+	return attacks
+}
+
+func BenchmarkSlidingAttacks(b *testing.B) {
+	occupied := bitboard.New(square.Square_e6, square.Square_c4, square.Square_g2)
+	for n := 0; n < b.N; n++ {
+		_ = bitboard.QueenAttacks(square.Square(n&63), occupied)
+	}
+}
@@ -0,0 +1,127 @@
+package bitboard
+
+import (
+	"fmt"
+
+	"gochess/pkg/notation/square"
+)
+
+// magic maps the relevant blockers of a sliding piece on one square to its
+// attacks with a multiply and shift (fancy magic bitboards).
+type magic struct {
+	mask    Bitboard
+	magic   uint64
+	shift   uint
+	attacks []Bitboard
+}
+
+func (m *magic) index(occupied Bitboard) uint {
+	return uint((uint64(occupied&m.mask) * m.magic) >> m.shift)
+}
+
+var (
+	rookMagics   [64]magic
+	bishopMagics [64]magic
+
+	// Shared attack tables the magics index into
+	rookTable   [0x19000]Bitboard
+	bishopTable [0x1480]Bitboard
+)
+
+// RookAttacks returns the squares a rook on the square attacks given the occupied squares.
+func RookAttacks(s square.Square, occupied Bitboard) Bitboard {
+	m := &rookMagics[s]
+	return m.attacks[m.index(occupied)]
+}
+
+// BishopAttacks returns the squares a bishop on the square attacks given the occupied squares.
+func BishopAttacks(s square.Square, occupied Bitboard) Bitboard {
+	m := &bishopMagics[s]
+	return m.attacks[m.index(occupied)]
+}
+
+// QueenAttacks returns the squares a queen on the square attacks given the occupied squares.
+func QueenAttacks(s square.Square, occupied Bitboard) Bitboard {
+	return RookAttacks(s, occupied) | BishopAttacks(s, occupied)
+}
+
+func initMagics() {
+	initSliderMagics(&rookMagics, rookMagicNumbers, rookTable[:], rookDirections)
+	initSliderMagics(&bishopMagics, bishopMagicNumbers, bishopTable[:], bishopDirections)
+}
+
+// initSliderMagics fills the attack table of every square through its magic number.
+func initSliderMagics(magics *[64]magic, numbers [64]uint64, table []Bitboard, directions []direction) {
+	offset := 0
+	for s := square.Square_a1; s <= square.Square_h8; s++ {
+		m := &magics[s]
+		m.magic = numbers[s]
+
+		// Board edges are never relevant blockers, unless the piece is on them
+		f, r := s.FileRank()
+		edges := ((Rank1 | Rank8) &^ RankMask(r)) | ((FileA | FileH) &^ FileMask(f))
+		m.mask = rayAttacks(s, Empty, directions) &^ edges
+		m.shift = uint(64 - m.mask.Count())
+
+		size := 1 << m.mask.Count()
+		m.attacks = table[offset : offset+size]
+		offset += size
+
+		// Enumerate every subset of the mask with the Carry-Rippler trick
+		filled := make([]bool, size)
+		for b := Empty; ; {
+			attacks := rayAttacks(s, b, directions)
+			index := m.index(b)
+			if filled[index] && m.attacks[index] != attacks {
+				panic(fmt.Sprintf("bad magic number for square %s", s))
+			}
+			m.attacks[index] = attacks
+			filled[index] = true
+
+			b = (b - m.mask) & m.mask
+			if b == 0 {
+				break
+			}
+		}
+	}
+}
+
+// Magic numbers found by a random search over sparse numbers
+var (
+	rookMagicNumbers = [64]uint64{
+		0x0A80004000801220, 0x10C0100040002000, 0x0100102000410009, 0x0B0021000C100008,
+		0x4080080080040002, 0x0200019004080200, 0x0400080A10112684, 0x20800A4D00062080,
+		0x2091800020804000, 0x0044401000200040, 0x1001002000401108, 0x1001800801100081,
+		0x0001000500080010, 0x1000808002000400, 0x0404000482100108, 0x0003000182610002,
+		0x0440848002C00420, 0x2010890040010021, 0x8800110020044300, 0x0208010100201000,
+		0x1222020004102008, 0x0000808002000400, 0x20040400094A9008, 0x0000420000804401,
+		0x0040002880004680, 0x0000200240100040, 0x0020008180201001, 0x01080080800C1000,
+		0x0104040080800800, 0x4800020080040080, 0x0002000200840108, 0x00A1000100006082,
+		0x8004400088800260, 0x0100804000802008, 0x0010008010802002, 0x000C801000800800,
+		0x0C51800402800800, 0x0002800200800400, 0x0000820804000110, 0x4003808042000401,
+		0x00208020C0018000, 0x4400402010004009, 0x22100400A800E000, 0x0E020021400A0013,
+		0x10A0080100110005, 0x0004010002004040, 0x0024080102040010, 0x4154089108420014,
+		0x0182400080002380, 0x0000400110802100, 0x0000100080200480, 0x100A000820401200,
+		0x8081004020801002, 0x0002000408100200, 0x03223A1008010C00, 0x000000831C014200,
+		0x4200208009001041, 0xC001004000881021, 0x1008200100100841, 0x0000082240920032,
+		0x4002000804201102, 0xB821000804000201, 0x4080C208102100A4, 0x02020900418C0CA2,
+	}
+	bishopMagicNumbers = [64]uint64{
+		0x40106000A1160020, 0x0230106090808800, 0x4010210041000800, 0x02240400980C2000,
+		0x1304030800402088, 0x140A0F1008000002, 0x0001043002088080, 0x0431240044102800,
+		0x0000400222021200, 0x0040080880809206, 0x0420044104250001, 0x0008841046010A40,
+		0x2000020210001000, 0x4000C20190080000, 0x0404020801041004, 0x0004004048241040,
+		0x8008802002104A20, 0x08080802B0840080, 0x1008082A42040020, 0x2118010402142012,
+		0x2002800400A08004, 0x2108080082012020, 0x2054038069080800, 0x0000400202020110,
+		0x0230404825040481, 0x1030310108012102, 0x8808020A11140105, 0x0014040038020808,
+		0x2084040018410040, 0x8409420001C11030, 0x000088904C020830, 0x00032A0401420080,
+		0xA204824014602422, 0xC9021A1308E00824, 0x0404020100420400, 0x2800600800048820,
+		0x00084A0020120080, 0x00041000800C1040, 0x2004081880004400, 0x0042040031250091,
+		0xC20A082008004400, 0x1124010882122800, 0x8842010101002081, 0x4001044200808808,
+		0x0000240102122400, 0x3082240806020221, 0x803010B218808040, 0x1034A40400400020,
+		0x4081040120690000, 0x00420A12090C8500, 0x0808420124090940, 0x1110050042020001,
+		0x0D60224099024000, 0x0100084218820081, 0x08882048088504A8, 0x2406088F01060390,
+		0x000202010C829000, 0x0260010421010810, 0x0004200A004208A0, 0x0222000800208821,
+		0x0083040004104421, 0x2011808810100224, 0x2102A02002208100, 0x0002420441020602,
+	}
+)
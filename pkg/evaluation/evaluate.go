@@ -1,43 +1,161 @@
 package evaluation
 
 import (
+	"gochess/pkg/bitboard"
 	"gochess/pkg/notation/piece"
 	"gochess/pkg/notation/position"
 )
 
+// MaxPhase is the game phase with all pieces on the board, 0 being a pawn endgame.
+const MaxPhase = 24
+
+// phaseWeights are the share of each piece type in the game phase.
+var phaseWeights = [...]int{
+	piece.Piece_Knight: 1,
+	piece.Piece_Bishop: 1,
+	piece.Piece_Rook:   2,
+	piece.Piece_Queen:  4,
+}
+
+// ==================== Terms ====================
+
+// Term is a set of evaluation terms that can be switched off, to measure what they are worth.
+// Material and piece-square tables are always on.
+type Term uint
+
+const (
+	Term_PawnStructure Term = 1 << iota
+	Term_Mobility
+	Term_PawnShield
+	Term_KingFiles   // Open files next to the king
+	Term_KingAttacks // Attacks on the squares around the king
+
+	Term_None Term = 0
+	Term_All  Term = Term_PawnStructure | Term_Mobility | Term_PawnShield | Term_KingFiles | Term_KingAttacks
+)
+
+// Terms lists each term that can be switched off.
+var Terms = []Term{Term_PawnStructure, Term_Mobility, Term_PawnShield, Term_KingFiles, Term_KingAttacks}
+
+var termNames = map[Term]string{
+	Term_PawnStructure: "PawnStructure",
+	Term_Mobility:      "Mobility",
+	Term_PawnShield:    "PawnShield",
+	Term_KingFiles:     "KingFiles",
+	Term_KingAttacks:   "KingAttacks",
+}
+
+func (t Term) String() string {
+	return termNames[t]
+}
+
+// ==================== Evaluator ====================
+
+// Evaluator evaluates positions, caching their pawn structures. It isn't safe for concurrent use.
+type Evaluator struct {
+	Params *Params
+	Terms  Term // Terms switched on
+	pawns  *PawnTable
+}
+
+func NewEvaluator() *Evaluator {
+	return &Evaluator{Params: &DefaultParams, Terms: Term_All, pawns: NewPawnTable(DefaultPawnTableSize)}
+}
+
+// SetParams changes the parameters, forgetting the pawn structures scored with the previous ones.
+func (e *Evaluator) SetParams(params *Params) {
+	e.Params = params
+	if e.pawns != nil {
+		e.pawns.Clear()
+	}
+}
+
+// Evaluate returns the score of the position in centipawns with the default parameters,
+// positive when white is better.
 func Evaluate(p *position.Position) int {
-	var total int
-	total += GetMaterialCount(p)
-	return total
-}
-
-func GetMaterialCount(p *position.Position) int {
-	var total int
-	for _, pc := range p.PieceList {
-		switch pc {
-		case piece.Piece_BlackPawn:
-			total += -1
-		case piece.Piece_BlackBishop, piece.Piece_BlackKnight:
-			total += -3
-		case piece.Piece_BlackRook:
-			total += -5
-		case piece.Piece_BlackQueen:
-			total += -9
-		case piece.Piece_BlackKing:
-			total += -200
-		case piece.Piece_WhitePawn:
-			total += 1
-		case piece.Piece_WhiteBishop, piece.Piece_WhiteKnight:
-			total += 3
-		case piece.Piece_WhiteRook:
-			total += 5
-		case piece.Piece_WhiteQueen:
-			total += 9
-		case piece.Piece_WhiteKing:
-			total += 200
-		case piece.Piece_None:
-		default:
+	e := Evaluator{Params: &DefaultParams, Terms: Term_All}
+	return e.Evaluate(p)
+}
+
+// Trace breaks the evaluation of the position with the default parameters down by component and color.
+func Trace(p *position.Position) Breakdown {
+	e := Evaluator{Params: &DefaultParams, Terms: Term_All}
+	return e.Trace(p)
+}
+
+// Evaluate returns the score of the position in centipawns, positive when white is better.
+func (e *Evaluator) Evaluate(p *position.Position) int {
+	var b Breakdown
+	e.evaluate(p, &b, true)
+	return b.Total
+}
+
+// Trace breaks the evaluation of the position down by component and color.
+func (e *Evaluator) Trace(p *position.Position) Breakdown {
+	var b Breakdown
+	e.evaluate(p, &b, false)
+	return b
+}
+
+// evaluate fills the breakdown of the position. The pawn table only knows the difference
+// between the colors, when used it is all on the side of white.
+func (e *Evaluator) evaluate(p *position.Position, b *Breakdown, useTable bool) {
+	params := e.Params
+	for sq, pc := range p.PieceList {
+		if pc == piece.Piece_None {
+			continue
 		}
+
+		// Look up the tables from the side of the piece
+		t, c := pc.Abs(), colorIndex(pc.IsWhite())
+		if pc.IsWhite() {
+			sq ^= 56
+		}
+		b.Components[Component_Material][c] = b.Components[Component_Material][c].Add(params.PieceValues[t])
+		b.Components[Component_PST][c] = b.Components[Component_PST][c].Add(params.PST[t][sq])
+	}
+
+	// Pawn structure
+	if e.Terms&Term_PawnStructure != 0 {
+		var passed bitboard.Bitboard
+		if useTable {
+			b.Components[Component_Pawns][0], passed = evaluatePawns(p, params, e.pawns)
+		} else {
+			for _, isWhite := range []bool{true, false} {
+				score, sidePassed := sidePawnStructure(p, params, isWhite)
+				b.Components[Component_Pawns][colorIndex(isWhite)] = score
+				passed |= sidePassed
+			}
+		}
+		for _, isWhite := range []bool{true, false} {
+			c := colorIndex(isWhite)
+			b.Components[Component_Pawns][c] = b.Components[Component_Pawns][c].Add(evaluatePassers(p, params, passed, isWhite))
+		}
+	}
+
+	// Pieces and king safety, attacks on a king counting against its side
+	for _, isWhite := range []bool{true, false} {
+		c := colorIndex(isWhite)
+		mobility, kingAttacks := evaluatePieces(p, params, e.Terms, isWhite)
+		b.Components[Component_Mobility][c] = mobility
+		b.Components[Component_KingSafety][c] = b.Components[Component_KingSafety][c].Add(evaluateKingShelter(p, params, e.Terms, isWhite))
+		b.Components[Component_KingSafety][1-c] = b.Components[Component_KingSafety][1-c].Sub(kingAttacks)
+	}
+
+	// Sum up
+	var score Score
+	for _, colors := range b.Components {
+		score = score.Add(colors[0]).Sub(colors[1])
+	}
+	b.Phase = Phase(p)
+	b.Total = score.Taper(b.Phase)
+}
+
+// Phase returns the game phase from the pieces left, from 0 in the endgame to MaxPhase.
+func Phase(p *position.Position) int {
+	phase := 0
+	for t := piece.Piece_Knight; t <= piece.Piece_Queen; t++ {
+		phase += phaseWeights[t] * p.PiecesOfType(t).Count()
 	}
-	return total
+	return min(phase, MaxPhase)
 }
@@ -3,16 +3,94 @@ package evaluation_test
 import (
 	"gochess/pkg/evaluation"
 	"gochess/pkg/notation/position"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// mirror returns the FEN with the board flipped vertically and the colors swapped.
+func mirror(fen position.FEN) position.FEN {
+	fields := strings.Fields(string(fen))
+	ranks := strings.Split(fields[0], "/")
+	for i, j := 0, len(ranks)-1; i < j; i, j = i+1, j-1 {
+		ranks[i], ranks[j] = ranks[j], ranks[i]
+	}
+	swapCase := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' {
+				return r - 'a' + 'A'
+			} else if r >= 'A' && r <= 'Z' {
+				return r - 'A' + 'a'
+			}
+			return r
+		}, s)
+	}
+	fields[0] = swapCase(strings.Join(ranks, "/"))
+	fields[1] = map[string]string{"w": "b", "b": "w"}[fields[1]]
+	if fields[2] != "-" {
+		fields[2] = swapCase(fields[2])
+	}
+	fields[3] = "-"
+	return position.FEN(strings.Join(fields, " "))
+}
+
+func evaluate(t *testing.T, fen position.FEN) int {
+	p, err := position.NewPosition(fen)
+	require.NoError(t, err)
+	return evaluation.Evaluate(p)
+}
+
 func TestEvaluate(t *testing.T) {
 	startingPosition, err := position.NewPosition(position.StartingFEN)
 	require.NoError(t, err)
 
 	assert.Equal(t, 0, evaluation.Evaluate(startingPosition))
-	assert.Equal(t, 0, evaluation.GetMaterialCount(startingPosition))
+
+	// Both sides are scored alike
+	for _, fen := range []position.FEN{
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+		"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+		"rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8",
+	} {
+		assert.Equal(t, evaluate(t, fen), -evaluate(t, mirror(fen)), fen)
+	}
+
+	// Material in centipawns
+	assert.Greater(t, evaluate(t, "4k3/8/8/8/8/8/8/3QK3 w - - 0 1"), 800)
+	assert.Less(t, evaluate(t, "4k3/8/8/8/8/8/8/3qK3 w - - 0 1"), -800)
+
+	// Knights are better developed
+	assert.Greater(t,
+		evaluate(t, "rnbqkbnr/pppppppp/8/8/8/5N2/PPPPPPPP/RNBQKB1R b KQkq - 1 1"),
+		evaluate(t, "rnbqkbnr/pppppppp/8/8/8/7N/PPPPPPPP/RNBQKB1R b KQkq - 1 1"))
+
+	// Kings are better centralized in the endgame, but sheltered in the middlegame
+	assert.Greater(t, evaluate(t, "4k3/4p3/8/8/3K4/8/4P3/8 w - - 0 1"), evaluate(t, "4k3/4p3/8/8/8/8/4P3/7K w - - 0 1"))
+	assert.Less(t,
+		evaluate(t, "r1bqkb1r/pppppppp/2n2n2/8/3K4/8/PPPPPPPP/RNBQ1BNR w kq - 0 1"),
+		evaluate(t, "r1bqkb1r/pppppppp/2n2n2/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"))
+}
+
+func TestPhase(t *testing.T) {
+	tests := []struct {
+		fen      position.FEN
+		expected int
+	}{
+		{position.StartingFEN, evaluation.MaxPhase},
+		{"4k3/4p3/8/8/8/8/4P3/4K3 w - - 0 1", 0},
+		{"r3k3/4p3/8/8/8/8/4P3/2B1K3 w - - 0 1", 3},
+		{"qqqqk3/8/8/8/8/8/8/QQQQK3 w - - 0 1", evaluation.MaxPhase},
+	}
+	for _, test := range tests {
+		p, err := position.NewPosition(test.fen)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, evaluation.Phase(p), test.fen)
+	}
+
+	score := evaluation.Score{MG: 100, EG: 200}
+	assert.Equal(t, 100, score.Taper(evaluation.MaxPhase))
+	assert.Equal(t, 200, score.Taper(0))
+	assert.Equal(t, 150, score.Taper(evaluation.MaxPhase/2))
 }
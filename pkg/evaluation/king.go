@@ -0,0 +1,95 @@
+package evaluation
+
+import (
+	"gochess/pkg/bitboard"
+	"gochess/pkg/notation/piece"
+	"gochess/pkg/notation/position"
+	"gochess/pkg/notation/square"
+)
+
+// evaluatePieces scores the mobility of the pieces of the side and their attacks on the
+// squares around the enemy king. Mobility counts the pseudo-legal moves, the targets
+// the move generator finds.
+func evaluatePieces(p *position.Position, params *Params, terms Term, isWhite bool) (mobility, kingAttacks Score) {
+	if terms&(Term_Mobility|Term_KingAttacks) == 0 {
+		return mobility, kingAttacks
+	}
+	occupied := p.Occupied()
+	ours := p.Colored(isWhite)
+
+	// Positions set up without a king have no king zone
+	var kingZone bitboard.Bitboard
+	if enemyKing := p.KingSquare(!isWhite); enemyKing != square.Square_Invalid {
+		kingZone = bitboard.KingAttacks[enemyKing].Set(enemyKing)
+	}
+
+	for t := piece.Piece_Knight; t <= piece.Piece_Queen; t++ {
+		for pieces := p.PiecesOfType(t) & ours; pieces != bitboard.Empty; {
+			s := pieces.PopLSB()
+
+			// Find attacks
+			var attacks bitboard.Bitboard
+			switch t {
+			case piece.Piece_Knight:
+				attacks = bitboard.KnightAttacks[s]
+			case piece.Piece_Bishop:
+				attacks = bitboard.BishopAttacks(s, occupied)
+			case piece.Piece_Rook:
+				attacks = bitboard.RookAttacks(s, occupied)
+			case piece.Piece_Queen:
+				attacks = bitboard.QueenAttacks(s, occupied)
+			}
+
+			if terms&Term_Mobility != 0 {
+				mobility = mobility.Add(params.Mobility[t].Mul((attacks &^ ours).Count()))
+			}
+			if terms&Term_KingAttacks != 0 {
+				kingAttacks = kingAttacks.Add(params.KingAttacks[t].Mul((attacks & kingZone).Count()))
+			}
+		}
+	}
+	return mobility, kingAttacks
+}
+
+// evaluateKingShelter scores the pawns in front of the king of the side and the files
+// next to it without pawns.
+func evaluateKingShelter(p *position.Position, params *Params, terms Term, isWhite bool) Score {
+	var score Score
+	if terms&(Term_PawnShield|Term_KingFiles) == 0 {
+		return score
+	}
+	king := p.KingSquare(isWhite)
+	if king == square.Square_Invalid {
+		return score
+	}
+	ourPawns := p.PiecesOfType(piece.Piece_Pawn) & p.Colored(isWhite)
+	theirPawns := p.PiecesOfType(piece.Piece_Pawn) & p.Colored(!isWhite)
+	kf, kr := king.FileRank()
+	forward := 1
+	if !isWhite {
+		forward = -1
+	}
+
+	for f := max(kf-1, 0); f <= min(kf+1, 7); f++ {
+		// Pawns one and two ranks ahead of the king
+		if terms&Term_PawnShield != 0 {
+			for i := range params.PawnShield {
+				r := int(kr) + forward*(i+1)
+				if r >= 0 && r < 8 && ourPawns.Has(square.NewSquare(f, square.Rank(r))) {
+					score = score.Add(params.PawnShield[i])
+					break
+				}
+			}
+		}
+
+		// Files without our pawns
+		if terms&Term_KingFiles != 0 && ourPawns&bitboard.FileMask(f) == bitboard.Empty {
+			if theirPawns&bitboard.FileMask(f) == bitboard.Empty {
+				score = score.Add(params.KingOpenFile)
+			} else {
+				score = score.Add(params.KingSemiOpenFile)
+			}
+		}
+	}
+	return score
+}
@@ -0,0 +1,56 @@
+package evaluation_test
+
+import (
+	"gochess/pkg/evaluation"
+	"gochess/pkg/notation/piece"
+	"gochess/pkg/notation/position"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPiecesAndKingSafety(t *testing.T) {
+	// Only the tested term counts, with a different order of magnitude by weight
+	var params evaluation.Params
+	params.Mobility[piece.Piece_Knight] = evaluation.Score{MG: 1, EG: 1}
+	params.Mobility[piece.Piece_Bishop] = evaluation.Score{MG: 10, EG: 10}
+	params.Mobility[piece.Piece_Rook] = evaluation.Score{MG: 100, EG: 100}
+	params.KingAttacks[piece.Piece_Knight] = evaluation.Score{MG: 1, EG: 1}
+	params.KingAttacks[piece.Piece_Queen] = evaluation.Score{MG: 100, EG: 100}
+	params.PawnShield = [2]evaluation.Score{{MG: 10, EG: 10}, {MG: 1, EG: 1}}
+	params.KingOpenFile = evaluation.Score{MG: -100, EG: -100}
+	params.KingSemiOpenFile = evaluation.Score{MG: -10, EG: -10}
+
+	tests := []struct {
+		name     string
+		terms    evaluation.Term
+		fen      position.FEN
+		expected int
+	}{
+		{"Central Knight", evaluation.Term_Mobility, "4k3/8/8/8/4N3/8/8/4K3 w - - 0 1", 8},
+		{"Knight In Corner", evaluation.Term_Mobility, "4k3/8/8/8/8/8/8/N3K3 w - - 0 1", 2},
+		{"Bishop", evaluation.Term_Mobility, "4k3/8/8/8/8/8/8/2B1K3 w - - 0 1", 70},
+		{"Rook Blocked By King", evaluation.Term_Mobility, "4k3/8/8/8/8/8/8/R3K3 w - - 0 1", 1000},
+		{"Knight Attacks", evaluation.Term_KingAttacks, "4k3/8/3N4/8/8/8/8/4K3 w - - 0 1", 2},
+		{"Queen Attacks", evaluation.Term_KingAttacks, "4k3/8/8/8/8/8/8/4KQ2 b - - 0 1", 200},
+		{"Pawn Shield", evaluation.Term_PawnShield, "4k3/8/8/8/8/7P/5PP1/6K1 w - - 0 1", 21},
+		{"King Files", evaluation.Term_KingFiles, "4k3/6p1/8/8/8/8/5P1P/6K1 w - - 0 1", 200},
+		{"Attacks Without King", evaluation.Term_Mobility | evaluation.Term_KingAttacks, "8/8/8/8/4N3/8/8/4K3 w - - 0 1", 8},
+		{"Shelter Without King", evaluation.Term_PawnShield | evaluation.Term_KingFiles, "4k3/8/8/8/8/8/5PPP/8 w - - 0 1", 210},
+		{"Kingless Side", evaluation.Term_All, "8/8/8/8/8/8/4P3/4K3 w - - 0 1", -190},
+		{"Switched Off", evaluation.Term_None, "4k3/6p1/8/8/4N3/8/5P1P/6K1 w - - 0 1", 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			evaluator := &evaluation.Evaluator{Params: &params, Terms: test.terms}
+			p, err := position.NewPosition(test.fen)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, evaluator.Evaluate(p))
+
+			mirrored, err := position.NewPosition(mirror(test.fen))
+			require.NoError(t, err)
+			assert.Equal(t, -test.expected, evaluator.Evaluate(mirrored))
+		})
+	}
+}
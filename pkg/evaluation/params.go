@@ -0,0 +1,237 @@
+package evaluation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"gochess/pkg/notation/piece"
+)
+
+// Score is a pair of middlegame and endgame scores in centipawns.
+type Score struct {
+	MG int
+	EG int
+}
+
+func (s Score) Add(o Score) Score {
+	return Score{s.MG + o.MG, s.EG + o.EG}
+}
+
+func (s Score) Sub(o Score) Score {
+	return Score{s.MG - o.MG, s.EG - o.EG}
+}
+
+func (s Score) Mul(n int) Score {
+	return Score{s.MG * n, s.EG * n}
+}
+
+// MarshalJSON writes the score as a [middlegame, endgame] pair.
+func (s Score) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]int{s.MG, s.EG})
+}
+
+func (s *Score) UnmarshalJSON(data []byte) error {
+	var pair [2]int
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	s.MG, s.EG = pair[0], pair[1]
+	return nil
+}
+
+// Taper interpolates between the middlegame and endgame scores by the game phase.
+func (s Score) Taper(phase int) int {
+	return (s.MG*phase + s.EG*(MaxPhase-phase)) / MaxPhase
+}
+
+// Params are the weights of the evaluation terms.
+type Params struct {
+	PieceValues [piece.Piece_King + 1]Score // By piece type
+
+	// By piece type and square, laid out as seen by white with a8 first
+	PST [piece.Piece_King + 1][64]Score
+
+	// Pawn structure, by rank as seen by the side of the pawn
+	Doubled     Score
+	Isolated    Score
+	Backward    Score
+	Connected   [8]Score
+	Passed      [8]Score
+	Unstoppable Score // Passed pawn the enemy king can't catch in a pawn endgame
+
+	// Pieces, by piece type
+	Mobility    [piece.Piece_King + 1]Score // By pseudo-legal move
+	KingAttacks [piece.Piece_King + 1]Score // By attacked square next to the enemy king
+
+	// King shelter
+	PawnShield       [2]Score // By pawn one and two ranks ahead of the king, on its file and next ones
+	KingOpenFile     Score    // By file without pawns on and next to the king file
+	KingSemiOpenFile Score    // By file with only enemy pawns
+}
+
+// ==================== Weights ====================
+
+// Weights returns every weight of the parameters, middlegame then endgame of each score,
+// so they can be tuned in place.
+func (p *Params) Weights() []*int {
+	var weights []*int
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		switch v.Kind() {
+		case reflect.Int:
+			weights = append(weights, v.Addr().Interface().(*int))
+		case reflect.Array:
+			for i := 0; i < v.Len(); i++ {
+				walk(v.Index(i))
+			}
+		case reflect.Struct:
+			for i := 0; i < v.NumField(); i++ {
+				walk(v.Field(i))
+			}
+		}
+	}
+	walk(reflect.ValueOf(p).Elem())
+	return weights
+}
+
+// ==================== Reading And Writing ====================
+
+// LoadParams reads parameters written as JSON, the missing ones keeping their default.
+func LoadParams(path string) (*Params, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	params := DefaultParams
+	if err := json.NewDecoder(f).Decode(&params); err != nil {
+		return nil, fmt.Errorf("invalid params %s: %w", path, err)
+	}
+	return &params, nil
+}
+
+// WriteJSON writes the parameters as JSON, each score being a [middlegame, endgame] pair
+// and each table by piece type on its own line.
+func (p *Params) WriteJSON(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	v := reflect.ValueOf(*p)
+	for i := 0; i < v.NumField(); i++ {
+		fmt.Fprintf(&buf, "\t%q: ", v.Type().Field(i).Name)
+		field := v.Field(i)
+		if field.Kind() == reflect.Array && field.Type().Elem().Kind() == reflect.Array {
+			buf.WriteString("[\n")
+			for j := 0; j < field.Len(); j++ {
+				data, err := json.Marshal(field.Index(j).Interface())
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(&buf, "\t\t%s%s\n", data, separator(j, field.Len()))
+			}
+			buf.WriteString("\t]")
+		} else {
+			data, err := json.Marshal(field.Interface())
+			if err != nil {
+				return err
+			}
+			buf.Write(data)
+		}
+		buf.WriteString(separator(i, v.NumField()) + "\n")
+	}
+	buf.WriteString("}\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// separator returns the comma after the element i of n, if not the last.
+func separator(i, n int) string {
+	if i < n-1 {
+		return ","
+	}
+	return ""
+}
+
+// WriteGo writes the parameters as the Go source of DefaultParams.
+func (p *Params) WriteGo(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.WriteString("package evaluation\n\nimport \"gochess/pkg/notation/piece\"\n\n")
+	buf.WriteString("// DefaultParams are the weights the evaluation uses unless given others.\n")
+	buf.WriteString("var DefaultParams = Params{\n")
+	v := reflect.ValueOf(*p)
+	for i := 0; i < v.NumField(); i++ {
+		fmt.Fprintf(&buf, "%s: %s,\n", v.Type().Field(i).Name, goValue(v.Field(i), true))
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+var pieceTypeNames = [piece.Piece_King + 1]string{"", "Pawn", "Knight", "Bishop", "Rook", "Queen", "King"}
+
+// goValue returns the Go literal of a score or an array of scores, with its type when typed.
+// Arrays by piece type leave out their zero elements, the others hold 8 elements by line.
+func goValue(v reflect.Value, typed bool) string {
+	if v.Kind() == reflect.Struct {
+		s := v.Interface().(Score)
+		if typed {
+			return fmt.Sprintf("Score{%d, %d}", s.MG, s.EG)
+		}
+		return fmt.Sprintf("{%d, %d}", s.MG, s.EG)
+	}
+
+	var sb strings.Builder
+	if typed {
+		sb.WriteString(goType(v.Type()))
+	}
+	sb.WriteString("{")
+	switch {
+	case v.Len() == len(pieceTypeNames):
+		sb.WriteString("\n")
+		for i := 0; i < v.Len(); i++ {
+			if !v.Index(i).IsZero() {
+				fmt.Fprintf(&sb, "piece.Piece_%s: %s,\n", pieceTypeNames[i], goValue(v.Index(i), false))
+			}
+		}
+	case v.Len() <= 4:
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(goValue(v.Index(i), false))
+		}
+	default:
+		for i := 0; i < v.Len(); i++ {
+			if i%8 == 0 {
+				sb.WriteString("\n")
+			} else {
+				sb.WriteString(" ")
+			}
+			sb.WriteString(goValue(v.Index(i), false) + ",")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+func goType(t reflect.Type) string {
+	if t.Kind() != reflect.Array {
+		return t.Name()
+	}
+	if t.Len() == len(pieceTypeNames) {
+		return "[piece.Piece_King + 1]" + goType(t.Elem())
+	}
+	return fmt.Sprintf("[%d]%s", t.Len(), goType(t.Elem()))
+}
@@ -0,0 +1,65 @@
+package evaluation_test
+
+import (
+	"bytes"
+	"gochess/pkg/evaluation"
+	"gochess/pkg/notation/piece"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamsWeights(t *testing.T) {
+	params := evaluation.DefaultParams
+	weights := params.Weights()
+	assert.Len(t, weights, 2*(7+7*64+3+8+8+1+7+7+2+2))
+
+	// Pawn middlegame value first
+	assert.Equal(t, 0, *weights[0])
+	assert.Equal(t, 82, *weights[2])
+	*weights[2]++
+	assert.Equal(t, 83, params.PieceValues[piece.Piece_Pawn].MG)
+	assert.Equal(t, 82, evaluation.DefaultParams.PieceValues[piece.Piece_Pawn].MG)
+}
+
+func TestParamsJSON(t *testing.T) {
+	dir := t.TempDir()
+
+	// Round trip
+	params := evaluation.DefaultParams
+	params.Passed[6] = evaluation.Score{MG: 1, EG: 2}
+	var buf bytes.Buffer
+	require.NoError(t, params.WriteJSON(&buf))
+	assert.Contains(t, buf.String(), "\t\"Doubled\": [-10,-20],\n")
+	path := filepath.Join(dir, "params.json")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+	loaded, err := evaluation.LoadParams(path)
+	require.NoError(t, err)
+	assert.Equal(t, params, *loaded)
+
+	// Missing parameters keep their default
+	require.NoError(t, os.WriteFile(path, []byte(`{"Isolated": [-1, -2]}`), 0o644))
+	loaded, err = evaluation.LoadParams(path)
+	require.NoError(t, err)
+	assert.Equal(t, evaluation.Score{MG: -1, EG: -2}, loaded.Isolated)
+	assert.Equal(t, evaluation.DefaultParams.PST, loaded.PST)
+
+	// Errors
+	require.NoError(t, os.WriteFile(path, []byte(`{"Isolated": -1}`), 0o644))
+	_, err = evaluation.LoadParams(path)
+	assert.ErrorContains(t, err, "invalid params")
+	_, err = evaluation.LoadParams(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestParamsWriteGo(t *testing.T) {
+	// The default parameters are generated
+	var buf bytes.Buffer
+	require.NoError(t, evaluation.DefaultParams.WriteGo(&buf))
+	expected, err := os.ReadFile("default_params.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), buf.String())
+}
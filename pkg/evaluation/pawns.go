@@ -0,0 +1,187 @@
+package evaluation
+
+import (
+	"gochess/pkg/bitboard"
+	"gochess/pkg/notation/piece"
+	"gochess/pkg/notation/position"
+	"gochess/pkg/notation/square"
+)
+
+// DefaultPawnTableSize is the number of entries of a pawn table.
+const DefaultPawnTableSize = 1 << 14
+
+// Masks by color, white first, and square
+var (
+	adjacentFiles [8]bitboard.Bitboard
+	forwardFile   [2][64]bitboard.Bitboard // Squares ahead on the same file
+	passedMask    [2][64]bitboard.Bitboard // Squares ahead on the same and adjacent files
+)
+
+func init() {
+	for f := square.File(0); f < 8; f++ {
+		if f > 0 {
+			adjacentFiles[f] |= bitboard.FileMask(f - 1)
+		}
+		if f < 7 {
+			adjacentFiles[f] |= bitboard.FileMask(f + 1)
+		}
+	}
+	for s := square.Square(0); s < 64; s++ {
+		f, r := s.FileRank()
+		for ahead := r + 1; ahead < 8; ahead++ {
+			forwardFile[0][s] |= bitboard.RankMask(ahead) & bitboard.FileMask(f)
+			passedMask[0][s] |= bitboard.RankMask(ahead) & (bitboard.FileMask(f) | adjacentFiles[f])
+		}
+		for ahead := r - 1; ahead >= 0; ahead-- {
+			forwardFile[1][s] |= bitboard.RankMask(ahead) & bitboard.FileMask(f)
+			passedMask[1][s] |= bitboard.RankMask(ahead) & (bitboard.FileMask(f) | adjacentFiles[f])
+		}
+	}
+}
+
+func colorIndex(isWhite bool) int {
+	if isWhite {
+		return 0
+	}
+	return 1
+}
+
+// relativeRank returns the rank of the square as seen by the side, 0 being its back rank.
+func relativeRank(s square.Square, isWhite bool) int {
+	_, r := s.FileRank()
+	if isWhite {
+		return int(r)
+	}
+	return 7 - int(r)
+}
+
+// distance returns the number of king moves between the squares.
+func distance(s1, s2 square.Square) int {
+	f1, r1 := s1.FileRank()
+	f2, r2 := s2.FileRank()
+	return max(int(f1-f2), int(f2-f1), int(r1-r2), int(r2-r1))
+}
+
+// ==================== Pawn Table ====================
+
+type pawnEntry struct {
+	key    uint64
+	score  Score
+	passed bitboard.Bitboard // Passed pawns of both colors
+}
+
+// PawnTable caches the pawn structure of positions by their pawn hash. The
+// structure only depends on the pawns, so it is often the same across a search.
+type PawnTable struct {
+	entries []pawnEntry
+}
+
+func NewPawnTable(size int) *PawnTable {
+	return &PawnTable{entries: make([]pawnEntry, max(size, 1))}
+}
+
+// Clear empties the table.
+func (t *PawnTable) Clear() {
+	clear(t.entries)
+}
+
+// ==================== Pawn Structure ====================
+
+// evaluatePawns returns the pawn structure score from white's side and the passed
+// pawns, from the table when it has them.
+func evaluatePawns(p *position.Position, params *Params, table *PawnTable) (Score, bitboard.Bitboard) {
+	if table == nil {
+		return pawnStructure(p, params)
+	}
+	e := &table.entries[p.PawnHash%uint64(len(table.entries))]
+	if e.key != p.PawnHash {
+		// An empty entry fits the positions without pawns, which hash to zero
+		e.key = p.PawnHash
+		e.score, e.passed = pawnStructure(p, params)
+	}
+	return e.score, e.passed
+}
+
+func pawnStructure(p *position.Position, params *Params) (Score, bitboard.Bitboard) {
+	white, whitePassed := sidePawnStructure(p, params, true)
+	black, blackPassed := sidePawnStructure(p, params, false)
+	return white.Sub(black), whitePassed | blackPassed
+}
+
+// sidePawnStructure scores the doubled, isolated, backward, connected and passed pawns of the side.
+func sidePawnStructure(p *position.Position, params *Params, isWhite bool) (Score, bitboard.Bitboard) {
+	c := colorIndex(isWhite)
+	ours := p.PiecesOfType(piece.Piece_Pawn) & p.Colored(isWhite)
+	theirs := p.PiecesOfType(piece.Piece_Pawn) & p.Colored(!isWhite)
+
+	var score Score
+	var passed bitboard.Bitboard
+	for pawns := ours; pawns != bitboard.Empty; {
+		s := pawns.PopLSB()
+		f, r := s.FileRank()
+		rank := relativeRank(s, isWhite)
+		neighbours := ours & adjacentFiles[f]
+
+		// Doubled pawns are counted behind the front one
+		if ours&forwardFile[c][s] != bitboard.Empty {
+			score = score.Add(params.Doubled)
+		}
+
+		// Isolated pawns have no neighbour, backward ones have none beside or behind
+		// and can't advance safely
+		if neighbours == bitboard.Empty {
+			score = score.Add(params.Isolated)
+		} else if neighbours&^passedMask[c][s] == bitboard.Empty {
+			stop := s + square.Square(8-16*c)
+			if bitboard.PawnAttack(isWhite, stop)&theirs != bitboard.Empty {
+				score = score.Add(params.Backward)
+			}
+		}
+
+		// Connected pawns stand side by side or defend each other
+		phalanx := neighbours & bitboard.RankMask(r)
+		supported := bitboard.PawnAttack(!isWhite, s) & ours
+		if phalanx|supported != bitboard.Empty {
+			score = score.Add(params.Connected[rank])
+		}
+
+		// Passed pawns have no pawn in their way
+		if theirs&passedMask[c][s] == bitboard.Empty && ours&forwardFile[c][s] == bitboard.Empty {
+			score = score.Add(params.Passed[rank])
+			passed = passed.Set(s)
+		}
+	}
+	return score, passed
+}
+
+// evaluatePassers returns the bonus of the side for its passed pawns the enemy king can't
+// catch, when the enemy has no piece left to stop them.
+func evaluatePassers(p *position.Position, params *Params, passed bitboard.Bitboard, isWhite bool) Score {
+	// Only the king and pawns can stop a passer
+	enemyPieces := p.Colored(!isWhite) &^ p.PiecesOfType(piece.Piece_Pawn) &^ p.PiecesOfType(piece.Piece_King)
+	if enemyPieces != bitboard.Empty {
+		return Score{}
+	}
+
+	// Apply the rule of the square to the free passers
+	enemyKing := p.KingSquare(!isWhite)
+	for pawns := passed & p.Colored(isWhite); pawns != bitboard.Empty; {
+		s := pawns.PopLSB()
+		if forwardFile[colorIndex(isWhite)][s]&p.Occupied() != bitboard.Empty {
+			continue
+		}
+		f, _ := s.FileRank()
+		promotion := square.NewSquare(f, square.Rank(7*(1-colorIndex(isWhite))))
+		moves := 7 - relativeRank(s, isWhite)
+		if relativeRank(s, isWhite) == 1 {
+			moves-- // Double push
+		}
+		if p.WhitesTurn != isWhite {
+			moves++
+		}
+		if enemyKing == square.Square_Invalid || distance(enemyKing, promotion) > moves {
+			return params.Unstoppable
+		}
+	}
+	return Score{}
+}
@@ -0,0 +1,69 @@
+package evaluation_test
+
+import (
+	"gochess/pkg/evaluation"
+	"gochess/pkg/notation/position"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPawnStructure(t *testing.T) {
+	// Only the pawn structure counts, with a different order of magnitude by term
+	params := evaluation.Params{
+		Doubled:     evaluation.Score{MG: -10, EG: -10},
+		Isolated:    evaluation.Score{MG: -20, EG: -20},
+		Backward:    evaluation.Score{MG: -40, EG: -40},
+		Unstoppable: evaluation.Score{MG: 10000, EG: 10000},
+	}
+	for rank := range params.Passed {
+		params.Connected[rank] = evaluation.Score{MG: 100 * rank, EG: 100 * rank}
+		params.Passed[rank] = evaluation.Score{MG: 1000 * rank, EG: 1000 * rank}
+	}
+	evaluator := &evaluation.Evaluator{Params: &params, Terms: evaluation.Term_PawnStructure}
+
+	tests := []struct {
+		name     string
+		fen      position.FEN
+		expected int
+	}{
+		{"Doubled Isolated", "4k3/8/8/8/8/3P4/3P4/4K3 w - - 0 1", -10 - 2*20 + 2000},
+		{"Backward", "4k3/8/8/8/4p3/2P5/3P4/4K3 w - - 0 1", 200 + 2000 - 40 + 20},
+		{"Phalanx", "4k3/pp6/8/8/8/8/PP6/4K3 w - - 0 1", 0},
+		{"Blocked", "4k3/8/8/3p4/3P4/8/8/4K3 w - - 0 1", 0},
+		{"Unstoppable", "8/4k3/8/P7/8/8/8/K7 w - - 0 1", -20 + 4000 + 10000},
+		{"Caught On Move", "8/4k3/8/P7/8/8/8/K7 b - - 0 1", -20 + 4000},
+		{"Caught By Knight", "8/4k3/8/P7/8/8/8/K6n w - - 0 1", -20 + 4000},
+		{"Double Push", "8/8/8/8/6k1/8/P7/K7 w - - 0 1", -20 + 1000 + 10000},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := position.NewPosition(test.fen)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, evaluator.Evaluate(p))
+
+			mirrored, err := position.NewPosition(mirror(test.fen))
+			require.NoError(t, err)
+			assert.Equal(t, -test.expected, evaluator.Evaluate(mirrored))
+		})
+	}
+}
+
+func TestEvaluatorPawnTable(t *testing.T) {
+	evaluator := evaluation.NewEvaluator()
+	for _, fen := range []position.FEN{
+		position.StartingFEN,
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R4RK1 b kq - 1 1",
+		"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+		"4k3/8/8/8/8/8/8/3QK3 w - - 0 1",
+	} {
+		p, err := position.NewPosition(fen)
+		require.NoError(t, err)
+
+		// Twice, the second time from the table
+		assert.Equal(t, evaluation.Evaluate(p), evaluator.Evaluate(p), fen)
+		assert.Equal(t, evaluation.Evaluate(p), evaluator.Evaluate(p), fen)
+	}
+}
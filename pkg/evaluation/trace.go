@@ -0,0 +1,60 @@
+package evaluation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Component is a group of evaluation terms shown apart in a trace.
+type Component int
+
+const (
+	Component_Material Component = iota
+	Component_PST
+	Component_Pawns
+	Component_Mobility
+	Component_KingSafety
+	component_Count
+)
+
+var componentNames = [component_Count]string{
+	Component_Material:   "Material",
+	Component_PST:        "PST",
+	Component_Pawns:      "Pawns",
+	Component_Mobility:   "Mobility",
+	Component_KingSafety: "King safety",
+}
+
+func (c Component) String() string {
+	return componentNames[c]
+}
+
+// Breakdown is an evaluation split by component and color.
+type Breakdown struct {
+	Components [component_Count][2]Score // By component and color, white first
+	Phase      int
+	Total      int // Tapered score in centipawns, positive when white is better
+}
+
+// String returns the breakdown as a table of middlegame and endgame scores.
+func (b Breakdown) String() string {
+	var sb strings.Builder
+	line := " ------------+-------------+-------------+-------------\n"
+	sb.WriteString("        Term |    White    |    Black    |    Total    \n")
+	sb.WriteString("             |   MG    EG  |   MG    EG  |   MG    EG  \n")
+	sb.WriteString(line)
+
+	var total Score
+	for c, colors := range b.Components {
+		diff := colors[0].Sub(colors[1])
+		total = total.Add(diff)
+		fmt.Fprintf(&sb, " %11s | %5d %5d | %5d %5d | %5d %5d\n", Component(c),
+			colors[0].MG, colors[0].EG, colors[1].MG, colors[1].EG, diff.MG, diff.EG)
+	}
+	sb.WriteString(line)
+	fmt.Fprintf(&sb, " %11s |             |             | %5d %5d\n\n", "Total", total.MG, total.EG)
+
+	fmt.Fprintf(&sb, "Phase: %d/%d\n", b.Phase, MaxPhase)
+	fmt.Fprintf(&sb, "Evaluation: %+d cp (white side)\n", b.Total)
+	return sb.String()
+}
@@ -0,0 +1,50 @@
+package evaluation_test
+
+import (
+	"gochess/pkg/evaluation"
+	"gochess/pkg/notation/piece"
+	"gochess/pkg/notation/position"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrace(t *testing.T) {
+	for _, fen := range []position.FEN{
+		position.StartingFEN,
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+		"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+		"8/4k3/8/P7/8/8/8/K7 w - - 0 1",
+	} {
+		p, err := position.NewPosition(fen)
+		require.NoError(t, err)
+
+		// The components add up to the evaluation
+		b := evaluation.Trace(p)
+		var total evaluation.Score
+		for _, colors := range b.Components {
+			total = total.Add(colors[0]).Sub(colors[1])
+		}
+		assert.Equal(t, evaluation.Evaluate(p), b.Total, fen)
+		assert.Equal(t, b.Total, total.Taper(b.Phase), fen)
+		assert.Equal(t, evaluation.Phase(p), b.Phase, fen)
+		assert.Equal(t, b, evaluation.NewEvaluator().Trace(p), fen)
+	}
+
+	// Each color on its side
+	p, err := position.NewPosition("4k3/8/8/8/8/8/8/3QK3 w - - 0 1")
+	require.NoError(t, err)
+	b := evaluation.Trace(p)
+	assert.Equal(t, evaluation.DefaultParams.PieceValues[piece.Piece_Queen], b.Components[evaluation.Component_Material][0])
+	assert.Equal(t, evaluation.Score{}, b.Components[evaluation.Component_Material][1])
+
+	lines := b.String()
+	for _, c := range []evaluation.Component{
+		evaluation.Component_Material, evaluation.Component_PST, evaluation.Component_Pawns,
+		evaluation.Component_Mobility, evaluation.Component_KingSafety,
+	} {
+		assert.Contains(t, lines, c.String())
+	}
+	assert.Contains(t, lines, "Phase: 4/24")
+}
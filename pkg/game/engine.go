@@ -0,0 +1,125 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/position"
+	"gochess/pkg/search"
+)
+
+// DefaultMoveTime is how long the engine thinks when no strength is set.
+const DefaultMoveTime = time.Second
+
+// ==================== Side ====================
+
+// Side is the color played by the human, the engine playing the others.
+type Side int
+
+const (
+	Side_White Side = iota
+	Side_Black
+	Side_Both // Two humans play each other
+	Side_None // The engine plays itself
+)
+
+var sideNames = map[Side]string{
+	Side_White: "white",
+	Side_Black: "black",
+	Side_Both:  "both",
+	Side_None:  "none",
+}
+
+func (s Side) String() string {
+	return sideNames[s]
+}
+
+// ParseSide returns the side with the name.
+func ParseSide(name string) (Side, error) {
+	for s, n := range sideNames {
+		if strings.EqualFold(name, n) {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid side %s, expected white, black, both or none", name)
+}
+
+// IsHuman reports if the human plays the color to move.
+func (s Side) IsHuman(whitesTurn bool) bool {
+	switch s {
+	case Side_White:
+		return whitesTurn
+	case Side_Black:
+		return !whitesTurn
+	default:
+		return s == Side_Both
+	}
+}
+
+// ==================== Engine ====================
+
+// Config sets up a game in the game loop.
+type Config struct {
+	Human Side
+
+	// Limits set the strength of the engine by depth, nodes or move time,
+	// it thinks DefaultMoveTime when none is set
+	Limits search.Limits
+}
+
+// engineMove searches the best move of the position within the limits of the config.
+func engineMove(s *search.Searcher, p *position.Position, config Config) search.Result {
+	limits := config.Limits
+	if limits.Depth == 0 && limits.Nodes == 0 && limits.MoveTime == 0 {
+		limits.MoveTime = DefaultMoveTime
+	}
+	return s.Search(context.Background(), p, limits)
+}
+
+// Analysis describes the result of a search of the position: the move, its evaluation
+// from the side of white and the principal variation.
+func Analysis(p *position.Position, result search.Result) string {
+	if result.Move == nil {
+		return "no legal move"
+	}
+	return fmt.Sprintf("%s (eval %s, depth %d, %d nodes)\nPV: %s",
+		result.Move.SAN(p), FormatScore(result.Score, p.WhitesTurn), result.Depth, result.Nodes, FormatPV(p, result.PV))
+}
+
+// FormatScore returns the score of the side to move from the side of white,
+// in pawns or moves to mate as in "+1.25" or "-#3".
+func FormatScore(score search.Score, whitesTurn bool) string {
+	sign := 1
+	if !whitesTurn {
+		sign = -1
+	}
+	switch mate := sign * score.MateMoves(); {
+	case !score.IsMate():
+		return fmt.Sprintf("%+.2f", float64(sign*int(score))/100)
+	case mate < 0:
+		return fmt.Sprintf("-#%d", -mate)
+	default:
+		return fmt.Sprintf("#%d", mate)
+	}
+}
+
+// FormatPV returns the moves played from the position in SAN with move numbers.
+func FormatPV(p *position.Position, pv []move.Move) string {
+	p = p.Copy()
+	var tokens []string
+	for i, m := range pv {
+		san := string(m.SAN(p))
+		switch {
+		case p.WhitesTurn:
+			san = fmt.Sprintf("%d. %s", p.FullmoveCount, san)
+		case i == 0:
+			san = fmt.Sprintf("%d... %s", p.FullmoveCount, san)
+		}
+		tokens = append(tokens, san)
+		p.Make(m.From, m.To, m.PromotedTo)
+	}
+	return strings.Join(tokens, " ")
+}
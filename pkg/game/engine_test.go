@@ -0,0 +1,91 @@
+package game_test
+
+import (
+	"testing"
+
+	"gochess/pkg/game"
+	"gochess/pkg/generation"
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/position"
+	"gochess/pkg/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSide(t *testing.T) {
+	tests := []struct {
+		name         string
+		expected     game.Side
+		white, black bool
+	}{
+		{"white", game.Side_White, true, false},
+		{"Black", game.Side_Black, false, true},
+		{"BOTH", game.Side_Both, true, true},
+		{"none", game.Side_None, false, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			side, err := game.ParseSide(test.name)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, side)
+			assert.Equal(t, test.white, side.IsHuman(true))
+			assert.Equal(t, test.black, side.IsHuman(false))
+		})
+	}
+
+	_, err := game.ParseSide("red")
+	assert.EqualError(t, err, "invalid side red, expected white, black, both or none")
+}
+
+func TestFormatScore(t *testing.T) {
+	tests := []struct {
+		score      search.Score
+		whitesTurn bool
+		expected   string
+	}{
+		{0, true, "+0.00"},
+		{125, true, "+1.25"},
+		{125, false, "-1.25"},
+		{-7, false, "+0.07"},
+		{search.MateIn(3), true, "#2"},
+		{search.MateIn(3), false, "-#2"},
+		{search.MatedIn(2), true, "-#1"},
+	}
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			assert.Equal(t, test.expected, game.FormatScore(test.score, test.whitesTurn))
+		})
+	}
+}
+
+func TestFormatPV(t *testing.T) {
+	p, err := position.NewPosition(position.StartingFEN)
+	require.NoError(t, err)
+	var pv []move.Move
+	q := p.Copy()
+	for _, san := range []move.SAN{"e4", "e5", "Nf3"} {
+		m, err := move.NewMoveFromSAN(q, san)
+		require.NoError(t, err)
+		pv = append(pv, m)
+		q.Make(m.From, m.To, m.PromotedTo)
+	}
+	assert.Equal(t, "1. e4 e5 2. Nf3", game.FormatPV(p, pv))
+	assert.Equal(t, position.StartingFEN, p.FEN())
+
+	// Black to move
+	p = generation.MakeMove(p, pv[0])
+	assert.Equal(t, "1... e5 2. Nf3", game.FormatPV(p, pv[1:]))
+	assert.Equal(t, "", game.FormatPV(p, nil))
+}
+
+func TestAnalysis(t *testing.T) {
+	p, err := position.NewPosition("6k1/5ppp/8/8/8/8/8/R3K3 w - - 0 40")
+	require.NoError(t, err)
+	m, err := move.NewMoveFromSAN(p, "Ra8#")
+	require.NoError(t, err)
+
+	result := search.Result{Move: &m, Score: search.MateIn(1), Depth: 3, Nodes: 120, PV: []move.Move{m}}
+	assert.Equal(t, "Ra8# (eval #1, depth 3, 120 nodes)\nPV: 40. Ra8#", game.Analysis(p, result))
+	assert.Equal(t, "no legal move", game.Analysis(p, search.Result{}))
+}
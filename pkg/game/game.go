@@ -1,42 +1,45 @@
 package game
 
 import (
+	"errors"
 	"fmt"
-	"gochess/pkg/generation"
-	"gochess/pkg/notation/position"
+	"io"
+	"os"
 
-	"github.com/manifoldco/promptui"
+	"github.com/chzyer/readline"
 )
 
-func GameLoop() {
-	boardPosition, err := position.NewPosition(position.StartingFEN)
+// GameLoop plays a game in the terminal, the engine replying to the human as set by the config.
+// It keeps reading commands after the game is over until the human quits.
+func GameLoop(config Config) {
+	s := NewSession(config, os.Stdout)
+	rl, err := readline.NewEx(&readline.Config{
+		Prompt:       "Move? ",
+		AutoComplete: completer{s},
+	})
 	if err != nil {
-		fmt.Println("invalid starting position")
+		fmt.Println("terminal failed: ", err)
 		return
 	}
+	defer rl.Close()
 
+	fmt.Println(`Type a move or "help", tab completes.`)
+	s.Advance()
 	for {
-		// Display position
-		fmt.Println(boardPosition.AsciiString())
-
-		// Display Moves
-		moves := generation.GenerateMoves(boardPosition)
-		fmt.Println(fmt.Sprint("Legal Moves: ", moves))
-
-		// Select Move
-		sel := promptui.Select{
-			Label: "Move?",
-			Items: moves,
-		}
-		i, _, err := sel.Run()
-		if err == promptui.ErrInterrupt {
+		// Read Move
+		line, err := rl.Readline()
+		if errors.Is(err, readline.ErrInterrupt) || errors.Is(err, io.EOF) {
 			break
 		} else if err != nil {
-			fmt.Println("select failed: ", err)
+			fmt.Println("read failed: ", err)
 			continue
 		}
 
 		// Make Move
-		boardPosition = generation.MakeMove(boardPosition, *moves[i])
+		if err := s.Handle(line); errors.Is(err, ErrQuit) {
+			break
+		} else if err != nil {
+			fmt.Println(err)
+		}
 	}
 }
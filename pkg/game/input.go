@@ -0,0 +1,74 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gochess/pkg/generation"
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/position"
+)
+
+// ParseMove returns the legal move of the position typed in PCN, LAN or SAN. The error
+// comes from the first notation the text is written in, telling why the move is illegal
+// or ambiguous.
+func ParseMove(p *position.Position, text string) (move.Move, error) {
+	text = strings.TrimSpace(text)
+	parsers := []func() (move.Move, error){
+		func() (move.Move, error) { return move.NewMoveFromPCN(p, move.PCN(text)) },
+		func() (move.Move, error) { return move.NewMoveFromLAN(p, move.LAN(text)) },
+		func() (move.Move, error) { return move.NewMoveFromSAN(p, move.SAN(text)) },
+	}
+
+	var firstErr error
+	for _, parse := range parsers {
+		m, err := parse()
+		if err == nil {
+			return m, nil
+		}
+		if firstErr == nil && !errors.Is(err, move.ErrSyntax) {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return move.Move{}, firstErr
+	}
+	return move.Move{}, fmt.Errorf("invalid move %q: expected SAN (Nf3), LAN (Ng1-f3) or PCN (g1f3), or a command", text)
+}
+
+// Completions returns the commands and the legal moves of the position in SAN, LAN and PCN
+// starting with the prefix, sorted.
+func Completions(p *position.Position, prefix string) []string {
+	var candidates []string
+	for _, c := range commands {
+		candidates = append(candidates, c.name)
+	}
+	for _, m := range generation.GenerateMoves(p) {
+		candidates = append(candidates, string(m.SAN(p)), string(m.LAN()), string(m.PCN()))
+	}
+
+	var completions []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			completions = append(completions, c)
+		}
+	}
+	slices.Sort(completions)
+	return slices.Compact(completions)
+}
+
+// completer completes the text typed at the prompt with the moves of the current position.
+type completer struct {
+	s *Session
+}
+
+func (c completer) Do(line []rune, pos int) ([][]rune, int) {
+	prefix := string(line[:pos])
+	var suffixes [][]rune
+	for _, completion := range Completions(c.s.Position(), prefix) {
+		suffixes = append(suffixes, []rune(completion[len(prefix):]))
+	}
+	return suffixes, len([]rune(prefix))
+}
@@ -0,0 +1,60 @@
+package game_test
+
+import (
+	"testing"
+
+	"gochess/pkg/game"
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/position"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMove(t *testing.T) {
+	tests := []struct {
+		name     string
+		fen      position.FEN
+		input    string
+		expected move.PCN
+		err      string
+	}{
+		{"PCN", position.StartingFEN, "e2e4", "e2e4", ""},
+		{"LAN", position.StartingFEN, "Ng1-f3", "g1f3", ""},
+		{"SAN", position.StartingFEN, "Nf3", "g1f3", ""},
+		{"Spaces", position.StartingFEN, " e4 ", "e2e4", ""},
+		{"Castling", "4k3/8/8/8/8/8/8/4K2R w K - 0 1", "O-O", "e1g1", ""},
+		{"Promotion", "8/P3k3/8/8/8/8/8/4K3 w - - 0 1", "a8=N", "a7a8n", ""},
+		{"Illegal PCN", position.StartingFEN, "e2e5", "", "illegal move e2e5: P on e2 can't move to e5"},
+		{"Illegal LAN", position.StartingFEN, "Ng1-f4", "", "illegal move Ng1-f4: N on g1 can't move to f4"},
+		{"Illegal SAN", position.StartingFEN, "Nd2", "", "illegal move Nd2: no N can move to d2"},
+		{"Ambiguous", "4k3/8/8/8/8/8/8/1N2KN2 w - - 0 1", "Nd2", "", "ambiguous move Nd2: N on b1, f1 can all move to d2"},
+		{"Invalid", position.StartingFEN, "e9", "",
+			`invalid move "e9": expected SAN (Nf3), LAN (Ng1-f3) or PCN (g1f3), or a command`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := position.NewPosition(test.fen)
+			require.NoError(t, err)
+
+			m, err := game.ParseMove(p, test.input)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, m.PCN())
+		})
+	}
+}
+
+func TestCompletions(t *testing.T) {
+	p, err := position.NewPosition(position.StartingFEN)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Na3", "Nb1a3", "Nb1c3", "Nc3", "Nf3", "Ng1f3", "Ng1h3", "Nh3"}, game.Completions(p, "N"))
+	assert.Equal(t, []string{"e2e3", "e2e4", "e3", "e4"}, game.Completions(p, "e"))
+	assert.Equal(t, []string{"h2h3", "h2h4", "h3", "h4", "help", "hint"}, game.Completions(p, "h"))
+	assert.Equal(t, []string{"resign"}, game.Completions(p, "r"))
+	assert.Empty(t, game.Completions(p, "Q"))
+}
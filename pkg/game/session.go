@@ -0,0 +1,211 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gochess/pkg/notation/pgn"
+	"gochess/pkg/notation/position"
+	"gochess/pkg/search"
+)
+
+const (
+	humanName  = "Human"
+	engineName = "gochess"
+)
+
+// ErrQuit is returned when the human quits the game.
+var ErrQuit = errors.New("quit")
+
+// command is typed at the prompt instead of a move.
+type command struct {
+	name string
+	help string
+	run  func(s *Session, args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"undo", "take back the last move played by the human", (*Session).undo},
+		{"flip", "turn the board around", (*Session).flip},
+		{"fen", "show the FEN of the position", (*Session).fen},
+		{"pgn", "show the game so far in PGN", (*Session).pgn},
+		{"hint", "ask the engine for a move", (*Session).hint},
+		{"resign", "resign the game", (*Session).resign},
+		{"help", "list the commands", (*Session).help},
+		{"quit", "leave the game", (*Session).quit},
+	}
+}
+
+// ==================== Session ====================
+
+// Session is a game in the terminal, the human typing moves or commands and the engine replying.
+type Session struct {
+	Config  Config
+	Game    *pgn.Game
+	Flipped bool // Black at the bottom of the board
+
+	out      io.Writer
+	searcher *search.Searcher
+	over     bool
+}
+
+// NewSession starts a game from the starting position, writing the board and the
+// engine moves to the output.
+func NewSession(config Config, out io.Writer) *Session {
+	g, _ := pgn.NewGame(position.StartingFEN)
+	g.Tags["Date"] = time.Now().Format("2006.01.02")
+	g.Tags["White"], g.Tags["Black"] = engineName, engineName
+	if config.Human.IsHuman(true) {
+		g.Tags["White"] = humanName
+	}
+	if config.Human.IsHuman(false) {
+		g.Tags["Black"] = humanName
+	}
+	return &Session{
+		Config:   config,
+		Game:     g,
+		Flipped:  config.Human == Side_Black,
+		out:      out,
+		searcher: search.NewSearcher(),
+	}
+}
+
+// Position returns the current position of the game.
+func (s *Session) Position() *position.Position {
+	return s.Game.Last().Position
+}
+
+// IsOver reports if the game ended, claimable draws counting as claimed.
+func (s *Session) IsOver() bool {
+	return s.over
+}
+
+// Advance shows the board and lets the engine play until it is the turn of the human
+// or the game is over.
+func (s *Session) Advance() {
+	for {
+		p := s.Position()
+		s.showBoard()
+
+		// Stop when the game is over, claiming draws for the players
+		if status := GameStatus(p); status != Status_Ongoing {
+			s.end(fmt.Sprintf("Game over by %s", status), status.Result(p.WhitesTurn))
+			return
+		}
+		if s.Config.Human.IsHuman(p.WhitesTurn) {
+			return
+		}
+
+		// Engine Move
+		result := engineMove(s.searcher, p, s.Config)
+		fmt.Fprintf(s.out, "Engine plays %s\n", Analysis(p, result))
+		s.Game.Last().AddMove(*result.Move)
+	}
+}
+
+// Handle plays the move or runs the command typed by the human, returning ErrQuit when
+// the human quits. Once the game is over only the commands are accepted.
+func (s *Session) Handle(input string) error {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return nil
+	}
+	for _, c := range commands {
+		if strings.EqualFold(fields[0], c.name) {
+			return c.run(s, fields[1:])
+		}
+	}
+
+	// Play Move
+	if s.over {
+		return fmt.Errorf("the game is over: undo a move to play on, or quit")
+	}
+	m, err := ParseMove(s.Position(), input)
+	if err != nil {
+		return err
+	}
+	s.Game.Last().AddMove(m)
+	s.Advance()
+	return nil
+}
+
+func (s *Session) showBoard() {
+	fmt.Fprintln(s.out, s.Position().AsciiStringFrom(!s.Flipped))
+}
+
+// end finishes the game with the result.
+func (s *Session) end(reason string, result pgn.Result) {
+	s.over = true
+	s.Game.Result = result
+	fmt.Fprintf(s.out, "%s: %s\n", reason, result)
+}
+
+// ==================== Commands ====================
+
+// undo takes back the moves played since the last move of the human.
+func (s *Session) undo(args []string) error {
+	for n := s.Game.Last(); !n.IsRoot(); {
+		n = n.Parent
+		if s.Config.Human.IsHuman(n.Position.WhitesTurn) {
+			n.Children = nil
+			s.over, s.Game.Result = false, pgn.Result_Unknown
+			s.Advance()
+			return nil
+		}
+	}
+	return fmt.Errorf("no move to undo")
+}
+
+func (s *Session) flip(args []string) error {
+	s.Flipped = !s.Flipped
+	s.showBoard()
+	return nil
+}
+
+func (s *Session) fen(args []string) error {
+	fmt.Fprintln(s.out, s.Position().FEN())
+	return nil
+}
+
+func (s *Session) pgn(args []string) error {
+	fmt.Fprintln(s.out, s.Game)
+	return nil
+}
+
+func (s *Session) hint(args []string) error {
+	p := s.Position()
+	result := engineMove(s.searcher, p, s.Config)
+	fmt.Fprintf(s.out, "Hint: %s\n", Analysis(p, result))
+	return nil
+}
+
+// resign ends the game, lost by the side to move.
+func (s *Session) resign(args []string) error {
+	if s.over {
+		return fmt.Errorf("the game is over")
+	}
+	result := pgn.Result_BlackWins
+	if !s.Position().WhitesTurn {
+		result = pgn.Result_WhiteWins
+	}
+	s.end("Game over by resignation", result)
+	return nil
+}
+
+func (s *Session) help(args []string) error {
+	fmt.Fprintln(s.out, "Type a move in SAN (Nf3), LAN (Ng1-f3) or PCN (g1f3), tab completes it. Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(s.out, "  %-8s%s\n", c.name, c.help)
+	}
+	return nil
+}
+
+func (s *Session) quit(args []string) error {
+	return ErrQuit
+}
@@ -0,0 +1,134 @@
+package game_test
+
+import (
+	"bytes"
+	"testing"
+
+	"gochess/pkg/game"
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/pgn"
+	"gochess/pkg/notation/position"
+	"gochess/pkg/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mainLine returns the SAN of the moves played in the session.
+func mainLine(s *game.Session) []move.SAN {
+	var sans []move.SAN
+	for _, n := range s.Game.MainLine() {
+		sans = append(sans, n.SAN())
+	}
+	return sans
+}
+
+func TestSessionHumans(t *testing.T) {
+	var out bytes.Buffer
+	s := game.NewSession(game.Config{Human: game.Side_Both}, &out)
+	s.Advance()
+	assert.Equal(t, "Human", s.Game.Tags["White"])
+	assert.Equal(t, "Human", s.Game.Tags["Black"])
+
+	// Moves in any notation, taken back one at a time
+	for _, input := range []string{"e4", "e7e5", "Ng1-f3"} {
+		require.NoError(t, s.Handle(input))
+	}
+	assert.Equal(t, []move.SAN{"e4", "e5", "Nf3"}, mainLine(s))
+	require.NoError(t, s.Handle("undo"))
+	assert.Equal(t, []move.SAN{"e4", "e5"}, mainLine(s))
+	assert.EqualError(t, s.Handle("Ke3"), "illegal move Ke3: K on e1 can't move to e3")
+	assert.Equal(t, []move.SAN{"e4", "e5"}, mainLine(s))
+
+	// Commands
+	out.Reset()
+	require.NoError(t, s.Handle("fen"))
+	assert.Equal(t, "rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq e6 0 2\n", out.String())
+	out.Reset()
+	require.NoError(t, s.Handle("pgn"))
+	assert.Contains(t, out.String(), "1. e4 e5 *")
+	out.Reset()
+	require.NoError(t, s.Handle("FLIP"))
+	assert.Contains(t, out.String(), "   h   g   f   e   d   c   b   a ")
+	assert.True(t, s.Flipped)
+	out.Reset()
+	require.NoError(t, s.Handle("help"))
+	assert.Contains(t, out.String(), "  resign  resign the game\n")
+	require.NoError(t, s.Handle(""))
+
+	// Fool's mate ends the game
+	for _, input := range []string{"undo", "undo", "f3", "e5", "g4", "Qh4#"} {
+		require.NoError(t, s.Handle(input))
+	}
+	assert.True(t, s.IsOver())
+	assert.Equal(t, pgn.Result_BlackWins, s.Game.Result)
+	assert.Contains(t, out.String(), "Game over by checkmate: 0-1")
+	assert.EqualError(t, s.Handle("resign"), "the game is over")
+	out.Reset()
+	require.NoError(t, s.Handle("pgn"))
+	assert.Contains(t, out.String(), "2. g4 Qh4# 0-1")
+	require.NoError(t, s.Handle("undo"))
+	assert.False(t, s.IsOver())
+	assert.Equal(t, pgn.Result_Unknown, s.Game.Result)
+	require.NoError(t, s.Handle("undo"))
+	require.NoError(t, s.Handle("undo"))
+	require.NoError(t, s.Handle("undo"))
+	assert.EqualError(t, s.Handle("undo"), "no move to undo")
+	assert.ErrorIs(t, s.Handle("quit"), game.ErrQuit)
+}
+
+func TestSessionEngine(t *testing.T) {
+	var out bytes.Buffer
+	s := game.NewSession(game.Config{Human: game.Side_White, Limits: search.Limits{Depth: 2}}, &out)
+	s.Advance()
+	assert.Equal(t, "Human", s.Game.Tags["White"])
+	assert.Equal(t, "gochess", s.Game.Tags["Black"])
+	assert.False(t, s.Flipped)
+
+	// The engine replies and shows its analysis
+	require.NoError(t, s.Handle("e4"))
+	require.Len(t, s.Game.MainLine(), 2)
+	assert.Contains(t, out.String(), "Engine plays ")
+	assert.Contains(t, out.String(), "PV: 1... ")
+	assert.True(t, s.Position().WhitesTurn)
+
+	// Undo takes back the reply with the move
+	require.NoError(t, s.Handle("undo"))
+	assert.Empty(t, s.Game.MainLine())
+	assert.EqualError(t, s.Handle("undo"), "no move to undo")
+
+	out.Reset()
+	require.NoError(t, s.Handle("hint"))
+	assert.Contains(t, out.String(), "Hint: ")
+	assert.Empty(t, s.Game.MainLine())
+
+	require.NoError(t, s.Handle("resign"))
+	assert.True(t, s.IsOver())
+	assert.Equal(t, pgn.Result_BlackWins, s.Game.Result)
+	assert.Contains(t, out.String(), "Game over by resignation: 0-1")
+	assert.EqualError(t, s.Handle("e4"), "the game is over: undo a move to play on, or quit")
+	assert.Empty(t, s.Game.MainLine())
+}
+
+func TestSessionEngineWhite(t *testing.T) {
+	var out bytes.Buffer
+	s := game.NewSession(game.Config{Human: game.Side_Black, Limits: search.Limits{Depth: 1}}, &out)
+	s.Advance()
+	assert.True(t, s.Flipped)
+	require.Len(t, s.Game.MainLine(), 1)
+	assert.False(t, s.Position().WhitesTurn)
+
+	// The first move of the engine can't be taken back
+	assert.EqualError(t, s.Handle("undo"), "no move to undo")
+	require.NoError(t, s.Handle("resign"))
+	assert.Equal(t, pgn.Result_WhiteWins, s.Game.Result)
+
+	// The engine plays itself to the end, here a mate in one
+	s = game.NewSession(game.Config{Human: game.Side_None, Limits: search.Limits{Depth: 1}}, &out)
+	p, err := position.NewPosition("6k1/5ppp/8/8/8/8/8/R3K3 w - - 0 1")
+	require.NoError(t, err)
+	s.Game.Root.Position = p
+	s.Advance()
+	assert.True(t, s.IsOver())
+	assert.Equal(t, pgn.Result_WhiteWins, s.Game.Result)
+}
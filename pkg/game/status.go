@@ -0,0 +1,101 @@
+package game
+
+import (
+	"gochess/pkg/generation"
+	"gochess/pkg/notation/pgn"
+	"gochess/pkg/notation/position"
+)
+
+// ==================== Status ====================
+
+type Status int
+
+const (
+	Status_Ongoing Status = iota
+	Status_Checkmate
+	Status_Stalemate
+	Status_InsufficientMaterial
+	Status_FivefoldRepetition
+	Status_SeventyFiveMoveRule
+	Status_ThreefoldRepetition
+	Status_FiftyMoveRule
+)
+
+func (s Status) String() string {
+	switch s {
+	case Status_Ongoing:
+		return "ongoing"
+	case Status_Checkmate:
+		return "checkmate"
+	case Status_Stalemate:
+		return "stalemate"
+	case Status_InsufficientMaterial:
+		return "insufficient material"
+	case Status_FivefoldRepetition:
+		return "fivefold repetition"
+	case Status_SeventyFiveMoveRule:
+		return "seventy-five-move rule"
+	case Status_ThreefoldRepetition:
+		return "threefold repetition"
+	case Status_FiftyMoveRule:
+		return "fifty-move rule"
+	default:
+		return "unknown"
+	}
+}
+
+// IsOver reports if the game ended without any player having to claim it.
+func (s Status) IsOver() bool {
+	return s != Status_Ongoing && !s.IsClaimable()
+}
+
+// IsClaimable reports if a player can claim a draw.
+func (s Status) IsClaimable() bool {
+	return s == Status_ThreefoldRepetition || s == Status_FiftyMoveRule
+}
+
+// Result returns the result of the game with the status, claimable draws counting as claimed.
+func (s Status) Result(whitesTurn bool) pgn.Result {
+	switch {
+	case s == Status_Ongoing:
+		return pgn.Result_Unknown
+	case s == Status_Checkmate && whitesTurn:
+		return pgn.Result_BlackWins
+	case s == Status_Checkmate:
+		return pgn.Result_WhiteWins
+	default:
+		return pgn.Result_Draw
+	}
+}
+
+// GameStatus returns how the game stands in the position. Repetitions are only
+// found among the moves played on the position, so it must carry its history.
+func GameStatus(p *position.Position) Status {
+	// Mate and stalemate end the game even on the move reaching another rule
+	if len(generation.GenerateMoves(p)) == 0 {
+		if p.InCheck() {
+			return Status_Checkmate
+		}
+		return Status_Stalemate
+	}
+
+	// Automatic draws
+	repetitions := p.Repetitions()
+	switch {
+	case p.HasInsufficientMaterial():
+		return Status_InsufficientMaterial
+	case repetitions >= 5:
+		return Status_FivefoldRepetition
+	case p.HalfmoveCount >= 150:
+		return Status_SeventyFiveMoveRule
+	}
+
+	// Claimable draws
+	switch {
+	case repetitions >= 3:
+		return Status_ThreefoldRepetition
+	case p.HalfmoveCount >= 100:
+		return Status_FiftyMoveRule
+	}
+	return Status_Ongoing
+}
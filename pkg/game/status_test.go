@@ -0,0 +1,57 @@
+package game_test
+
+import (
+	"testing"
+
+	"gochess/pkg/game"
+	"gochess/pkg/generation"
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/pgn"
+	"gochess/pkg/notation/position"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGameStatus(t *testing.T) {
+	shuffle := []move.SAN{"Nf3", "Nf6", "Ng1", "Ng8"}
+	tests := []struct {
+		name           string
+		fen            position.FEN
+		moves          []move.SAN
+		expected       game.Status
+		expectedResult pgn.Result
+	}{
+		{"Starting Position", position.StartingFEN, nil, game.Status_Ongoing, pgn.Result_Unknown},
+		{"Fool's Mate", position.StartingFEN, []move.SAN{"f3", "e5", "g4", "Qh4#"}, game.Status_Checkmate, pgn.Result_BlackWins},
+		{"Back Rank Mate", "6k1/5ppp/8/8/8/8/8/R3K3 w - - 0 1", []move.SAN{"Ra8#"}, game.Status_Checkmate, pgn.Result_WhiteWins},
+		{"Stalemate", "7k/5Q2/6K1/8/8/8/8/8 b - - 0 1", nil, game.Status_Stalemate, pgn.Result_Draw},
+		{"Kings Only", "8/8/4k3/8/8/3K4/8/8 w - - 0 1", nil, game.Status_InsufficientMaterial, pgn.Result_Draw},
+		{"Lone Knight", "8/8/4k3/8/8/3K4/3N4/8 w - - 0 1", nil, game.Status_InsufficientMaterial, pgn.Result_Draw},
+		{"Same Color Bishops", "8/8/4k3/2b5/8/3K4/3B4/8 w - - 0 1", nil, game.Status_InsufficientMaterial, pgn.Result_Draw},
+		{"Opposite Color Bishops", "8/8/4k3/1b6/8/3K4/3B4/8 w - - 0 1", nil, game.Status_Ongoing, pgn.Result_Unknown},
+		{"Two Knights", "8/8/4k3/8/8/3K4/3NN3/8 w - - 0 1", nil, game.Status_Ongoing, pgn.Result_Unknown},
+		{"Twofold Repetition", position.StartingFEN, shuffle, game.Status_Ongoing, pgn.Result_Unknown},
+		{"Threefold Repetition", position.StartingFEN, append(shuffle, shuffle...), game.Status_ThreefoldRepetition, pgn.Result_Draw},
+		{"Fivefold Repetition", position.StartingFEN, append(append(append(shuffle, shuffle...), shuffle...), shuffle...),
+			game.Status_FivefoldRepetition, pgn.Result_Draw},
+		{"Pawn Move Resets Repetitions", position.StartingFEN, append(append(shuffle, "e4", "e5"), shuffle...), game.Status_Ongoing, pgn.Result_Unknown},
+		{"Fifty-Move Rule", "8/8/4k3/8/8/3K4/3R4/8 b - - 99 80", []move.SAN{"Ke5"}, game.Status_FiftyMoveRule, pgn.Result_Draw},
+		{"Seventy-Five-Move Rule", "8/8/4k3/8/8/3K4/3R4/8 b - - 149 80", []move.SAN{"Ke5"}, game.Status_SeventyFiveMoveRule, pgn.Result_Draw},
+		{"Mate On The Hundredth Halfmove", "6k1/5ppp/8/8/8/8/8/R3K3 w - - 99 80", []move.SAN{"Ra8#"}, game.Status_Checkmate, pgn.Result_WhiteWins},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := position.NewPosition(test.fen)
+			require.NoError(t, err)
+			for _, san := range test.moves {
+				m, err := move.NewMoveFromSAN(p, san)
+				require.NoError(t, err)
+				p = generation.MakeMove(p, m)
+			}
+			status := game.GameStatus(p)
+			assert.Equal(t, test.expected, status)
+			assert.Equal(t, test.expectedResult, status.Result(p.WhitesTurn))
+		})
+	}
+}
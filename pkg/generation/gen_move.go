@@ -1,244 +1,283 @@
 package generation
 
 import (
-	"math"
-
+	"gochess/pkg/bitboard"
 	"gochess/pkg/notation/move"
 	"gochess/pkg/notation/piece"
 	"gochess/pkg/notation/position"
 	"gochess/pkg/notation/square"
 )
 
-func GenerateMoves(p *position.Position) (moveList move.MoveList) {
-	defer func() {
-		moveList.Sort()
-	}()
+// MoveKind selects which of the legal moves to generate.
+type MoveKind int
+
+const (
+	MoveKind_All   MoveKind = iota
+	MoveKind_Noisy          // Captures and promotions
+	MoveKind_Quiet          // Every other move, castling included
+)
+
+// GenerateMoves returns the legal moves sorted by piece and squares.
+func GenerateMoves(p *position.Position) move.MoveList {
+	moves := GenerateMovesOfKind(p, MoveKind_All)
+	moves.Sort()
+	return moves
+}
 
+// GenerateMovesOfKind returns the legal moves of the kind, unsorted.
+func GenerateMovesOfKind(p *position.Position, kind MoveKind) move.MoveList {
 	// Find King
-	inverter := 1
-	if !p.WhitesTurn {
-		inverter = -1
-	}
-	var kingSquare square.Square
-	for squareInt, pc := range p.PieceList {
-		if pc == piece.Piece_WhiteKing*piece.Piece(inverter) {
-			kingSquare = square.Square(squareInt)
-		}
+	kingSquare := p.KingSquare(p.WhitesTurn)
+	if kingSquare == square.Square_Invalid {
+		return move.MoveList{}
 	}
 
 	// Generate all Checks on King
-	checkMoves, pinnedPieces := GenerateChecksAndPins(p, kingSquare)
+	checkers, pinned := checkersAndPinned(p, kingSquare)
 
-	if len(checkMoves) >= 2 {
+	// Generate pseudo legal moves
+	var psuedoLegalMoves move.MoveList
+	if checkers.MoreThanOne() {
 		// Double Check, Only King moves are valid
-		return GenerateKingMoves(p, kingSquare)
+		psuedoLegalMoves = appendKingMoves(p, move.MoveList{}, kingSquare, kind)
+	} else {
+		psuedoLegalMoves = generatePseudoLegalMoves(p, kind)
 	}
 
-	// Generate pseudo legal moves
-	psuedoLegalMoves := GeneratePseudoLegalMoves(p)
-
-	// Filter moves of pinned pieces
-	for _, pinnedPiece := range pinnedPieces {
-		psuedoLegalMoves = psuedoLegalMoves.FindMovesNotFrom(pinnedPiece)
+	// Squares a non king move has to land on to resolve a check
+	checkResolvingSquares := bitboard.Full
+	if checkers != bitboard.Empty {
+		checkResolvingSquares = bitboard.Between(kingSquare, checkers.LSB()) | checkers
 	}
 
-	if len(checkMoves) == 1 {
-		// Single Check, Only King moves or blocking moves
-
-		// Pre-allocate move list
-		moves := make(move.MoveList, 0, len(psuedoLegalMoves))
+	moves := psuedoLegalMoves[:0]
+	for _, m := range psuedoLegalMoves {
+		// King moves can't land on or pass through attacked squares
+		if m.From == kingSquare {
+			if isLegalKingMove(p, m, checkers != bitboard.Empty) {
+				moves = append(moves, m)
+			}
+			continue
+		}
 
-		// King Moves
-		moves = append(moves, psuedoLegalMoves.FindMovesFrom(kingSquare)...)
+		// En passant can uncover the king in ways pins don't describe
+		if m.Piece.IsPawn() && m.To == p.EnPassantSquare {
+			if isLegalEnPassant(p, m, kingSquare) {
+				moves = append(moves, m)
+			}
+			continue
+		}
 
-		// Moves to capture checking piece
-		moves = append(moves, psuedoLegalMoves.FindMovesTo(checkMoves[0].From)...)
+		// Pinned pieces can only move along their pin ray
+		if pinned.Has(m.From) && !bitboard.Line(kingSquare, m.From).Has(m.To) {
+			continue
+		}
 
-		// Blocking Moves
-		for _, square := range square.SquaresInBetween(kingSquare, checkMoves[0].From) {
-			moves = append(moves, psuedoLegalMoves.FindMovesTo(square)...)
+		// Single Check, only capturing or blocking moves
+		if !checkResolvingSquares.Has(m.To) {
+			continue
 		}
 
-		return moves
+		moves = append(moves, m)
 	}
 
-	return psuedoLegalMoves
+	return moves
 }
 
-func GenerateChecksAndPins(p *position.Position, kingSquare square.Square) (move.MoveList, []square.Square) {
-	checkMoves := move.MoveList{}
-	pinnedSquares := []square.Square{}
+func isLegalKingMove(p *position.Position, m *move.Move, inCheck bool) bool {
+	// Remove the king so it can't hide behind its own square on a ray
+	occupied := p.Occupied().Clear(m.From)
+	them := p.Colored(!p.WhitesTurn)
+	if p.AttackersTo(m.To, occupied)&them != bitboard.Empty {
+		return false
+	}
+	if !m.IsCastling {
+		return true
+	}
 
-	inverter := 1
-	if !p.WhitesTurn {
-		inverter = -1
+	// Can't castle out of or through check
+	if inCheck {
+		return false
+	}
+	for path := bitboard.Between(m.From, m.To); path != bitboard.Empty; {
+		if p.AttackersTo(path.PopLSB(), occupied)&them != bitboard.Empty {
+			return false
+		}
 	}
+	return true
+}
 
-	f, r := kingSquare.FileRank()
+func isLegalEnPassant(p *position.Position, m *move.Move, kingSquare square.Square) bool {
+	// Look for attacks on the king with the board as it is after the capture
+	_, fromRank := m.From.FileRank()
+	toFile, _ := m.To.FileRank()
+	capturedSquare := square.NewSquare(toFile, fromRank)
 
-	// Find Knight/Pawn checks
-	for _, pair := range append(KnightMovementPairs, []MovementPair{{1 * inverter, 1}, {1 * inverter, -1}}...) {
-		// Check square is valid
-		fromSquare, err := square.NewSquareCheck(f+square.File(pair.FP), r+square.Rank(pair.RP))
-		if err != nil {
-			continue
-		}
+	occupied := p.Occupied().Clear(m.From).Clear(capturedSquare).Set(m.To)
+	them := p.Colored(!p.WhitesTurn).Clear(capturedSquare)
+	return p.AttackersTo(kingSquare, occupied)&them == bitboard.Empty
+}
 
-		// Create Move
-		move := GenerateMove(p, fromSquare, kingSquare)
-		if move == nil {
-			continue
-		}
+// Pin is a piece that can't leave the ray between its king and the pinning piece.
+type Pin struct {
+	Square square.Square
+	Pinner square.Square
+}
 
-		// Add move
-		if move.IsCapture {
-			checkMoves = append(checkMoves, move)
-		}
+func GenerateChecksAndPins(p *position.Position, kingSquare square.Square) (move.MoveList, []Pin) {
+	checkMoves := move.MoveList{}
+	pins := []Pin{}
+
+	// Find checks
+	checkers, _ := checkersAndPinned(p, kingSquare)
+	for checkers != bitboard.Empty {
+		fromSquare := checkers.PopLSB()
+		checkMoves = append(checkMoves, &move.Move{
+			From:      fromSquare,
+			To:        kingSquare,
+			Piece:     p.PieceAt(fromSquare),
+			IsCapture: true,
+		})
 	}
 
-	// Find Bishop/Queen checks and pinned pieces
-	for _, pair := range BishopMovementPairs {
-		var possiblePinnedPiece *square.Square
-		for i := 1; i <= 7; i++ {
-			// Check square is valid
-			fromSquare, err := square.NewSquareCheck(f+square.File(pair.FP*i), r+square.Rank(pair.RP*i))
-			if err != nil {
-				break
-			}
+	// Find pinned pieces
+	for snipers := sliderSnipers(p, kingSquare); snipers != bitboard.Empty; {
+		pinner := snipers.PopLSB()
+		blockers := bitboard.Between(kingSquare, pinner) & p.Occupied()
+		if blockers.Count() == 1 && blockers&p.Colored(p.WhitesTurn) != bitboard.Empty {
+			pins = append(pins, Pin{Square: blockers.LSB(), Pinner: pinner})
+		}
+	}
 
-			// Create move
-			move := GenerateMove(p, fromSquare, kingSquare)
-			if move == nil {
-				possiblePinnedPiece = &fromSquare
-				continue
-			}
+	return checkMoves, pins
+}
 
-			// Add move
-			pieceAbsVal := piece.Piece(math.Abs(float64(move.Piece)))
-			// TODO fix
-			if move.IsCapture && (pieceAbsVal == piece.Piece_WhiteBishop || pieceAbsVal == piece.Piece_WhiteQueen) {
-				if possiblePinnedPiece != nil {
-					pinnedSquares = append(pinnedSquares, *possiblePinnedPiece)
-				} else {
-					checkMoves = append(checkMoves, move)
-					break
-				}
-			}
+// checkersAndPinned returns the opponent pieces checking the king and the pieces
+// of the side to move that are pinned to it.
+func checkersAndPinned(p *position.Position, kingSquare square.Square) (bitboard.Bitboard, bitboard.Bitboard) {
+	us := p.Colored(p.WhitesTurn)
+	them := p.Colored(!p.WhitesTurn)
+	checkers := p.AttackersTo(kingSquare, p.Occupied()) & them
+
+	var pinned bitboard.Bitboard
+	for snipers := sliderSnipers(p, kingSquare) &^ checkers; snipers != bitboard.Empty; {
+		blockers := bitboard.Between(kingSquare, snipers.PopLSB()) & p.Occupied()
+		if !blockers.MoreThanOne() && blockers&us != bitboard.Empty {
+			pinned |= blockers
 		}
 	}
+	return checkers, pinned
+}
 
-	// Find Rook or Queen checks
-
-	return checkMoves, pinnedSquares
+// sliderSnipers returns the opponent sliders that would attack the king on an empty board.
+func sliderSnipers(p *position.Position, kingSquare square.Square) bitboard.Bitboard {
+	them := p.Colored(!p.WhitesTurn)
+	queens := p.PiecesOfType(piece.Piece_Queen)
+	rooks := (p.PiecesOfType(piece.Piece_Rook) | queens) & them
+	bishops := (p.PiecesOfType(piece.Piece_Bishop) | queens) & them
+	return (bitboard.RookAttacks(kingSquare, bitboard.Empty) & rooks) |
+		(bitboard.BishopAttacks(kingSquare, bitboard.Empty) & bishops)
 }
 
 // ==================== Pseudo-Legal Moves ====================
 
 func GeneratePseudoLegalMoves(p *position.Position) move.MoveList {
-	moves := move.MoveList{}
-
-	for i, pieceVal := range p.PieceList {
-		fromSquare := square.Square(i)
-		if p.WhitesTurn {
-			switch pieceVal {
-			case piece.Piece_WhitePawn:
-				moves = append(moves, GeneratePawnMoves(p, fromSquare)...)
-			case piece.Piece_WhiteKnight:
-				moves = append(moves, GenerateKnightMoves(p, fromSquare)...)
-			case piece.Piece_WhiteBishop:
-				moves = append(moves, GenerateBishopMoves(p, fromSquare)...)
-			case piece.Piece_WhiteRook:
-				moves = append(moves, GenerateRookMoves(p, fromSquare)...)
-			case piece.Piece_WhiteQueen:
-				moves = append(moves, GenerateQueenMoves(p, fromSquare)...)
-			case piece.Piece_WhiteKing:
-				moves = append(moves, GenerateKingMoves(p, fromSquare)...)
-			}
-		} else {
-			switch pieceVal {
-			case piece.Piece_BlackPawn:
-				moves = append(moves, GeneratePawnMoves(p, fromSquare)...)
-			case piece.Piece_BlackKnight:
-				moves = append(moves, GenerateKnightMoves(p, fromSquare)...)
-			case piece.Piece_BlackBishop:
-				moves = append(moves, GenerateBishopMoves(p, fromSquare)...)
-			case piece.Piece_BlackRook:
-				moves = append(moves, GenerateRookMoves(p, fromSquare)...)
-			case piece.Piece_BlackQueen:
-				moves = append(moves, GenerateQueenMoves(p, fromSquare)...)
-			case piece.Piece_BlackKing:
-				moves = append(moves, GenerateKingMoves(p, fromSquare)...)
-			}
-		}
-	}
+	return generatePseudoLegalMoves(p, MoveKind_All)
+}
 
-	return moves
+// targetsOfKind returns the squares moves of the kind can land on, besides pawn moves.
+func targetsOfKind(p *position.Position, kind MoveKind) bitboard.Bitboard {
+	switch kind {
+	case MoveKind_Noisy:
+		return p.Colored(!p.WhitesTurn)
+	case MoveKind_Quiet:
+		return ^p.Occupied()
+	default:
+		return bitboard.Full
+	}
 }
 
-func GeneratePawnMoves(p *position.Position, fromSquare square.Square) move.MoveList {
-	moves := move.MoveList{}
+func generatePseudoLegalMoves(p *position.Position, kind MoveKind) move.MoveList {
+	moves := make(move.MoveList, 0, 48)
+	targets := targetsOfKind(p, kind)
 
-	inverter := 1
+	inverter := piece.Piece(1)
 	if !p.WhitesTurn {
 		inverter = -1
 	}
 
-	f, r := fromSquare.FileRank()
-
-	// Check pawn movements
-	forwardMovements := []int{1}
-	if (p.WhitesTurn && r == square.Rank2) || (!p.WhitesTurn && r == square.Rank7) {
-		forwardMovements = append(forwardMovements, 2)
+	for pawns := p.Pieces(piece.Piece_WhitePawn * inverter); pawns != bitboard.Empty; {
+		moves = appendPawnMoves(p, moves, pawns.PopLSB(), kind)
+	}
+	for knights := p.Pieces(piece.Piece_WhiteKnight * inverter); knights != bitboard.Empty; {
+		fromSquare := knights.PopLSB()
+		moves = appendTargetMoves(p, moves, fromSquare, bitboard.KnightAttacks[fromSquare]&targets)
+	}
+	for bishops := p.Pieces(piece.Piece_WhiteBishop * inverter); bishops != bitboard.Empty; {
+		fromSquare := bishops.PopLSB()
+		moves = appendTargetMoves(p, moves, fromSquare, bitboard.BishopAttacks(fromSquare, p.Occupied())&targets)
+	}
+	for rooks := p.Pieces(piece.Piece_WhiteRook * inverter); rooks != bitboard.Empty; {
+		fromSquare := rooks.PopLSB()
+		moves = appendTargetMoves(p, moves, fromSquare, bitboard.RookAttacks(fromSquare, p.Occupied())&targets)
+	}
+	for queens := p.Pieces(piece.Piece_WhiteQueen * inverter); queens != bitboard.Empty; {
+		fromSquare := queens.PopLSB()
+		moves = appendTargetMoves(p, moves, fromSquare, bitboard.QueenAttacks(fromSquare, p.Occupied())&targets)
+	}
+	if kingSquare := p.KingSquare(p.WhitesTurn); kingSquare != square.Square_Invalid {
+		moves = appendKingMoves(p, moves, kingSquare, kind)
 	}
-	for _, rp := range forwardMovements {
-		// Check square is valid
-		toSquare, err := square.NewSquareCheck(f, r+square.Rank(rp*inverter))
-		if err != nil {
-			break
-		}
 
-		// Check type of move
-		move := GenerateMove(p, fromSquare, toSquare)
-		if move == nil {
-			break
-		}
+	return moves
+}
 
-		// Add move
-		if !move.IsCapture {
-			// Check for promotion
-			_, r := move.To.FileRank()
-			if (r == 8 && move.Piece == piece.Piece_WhitePawn) ||
-				(r == 1 && move.Piece == piece.Piece_BlackPawn) {
-				moves = append(moves, GeneratePromotionMoves(p, move)...)
-			} else {
-				moves = append(moves, move)
-			}
-		}
+func GeneratePawnMoves(p *position.Position, fromSquare square.Square) move.MoveList {
+	return appendPawnMoves(p, move.MoveList{}, fromSquare, MoveKind_All)
+}
+
+func appendPawnMoves(p *position.Position, moves move.MoveList, fromSquare square.Square, kind MoveKind) move.MoveList {
+	pc := p.PieceAt(fromSquare)
+	from := bitboard.New(fromSquare)
+	empty := ^p.Occupied()
+
+	// Check pawn movements, pawns can't push into any piece
+	var push, doublePush bitboard.Bitboard
+	if pc.IsWhite() {
+		push = from.North() & empty
+		doublePush = (push & bitboard.RankMask(square.Rank3)).North() & empty
+	} else {
+		push = from.South() & empty
+		doublePush = (push & bitboard.RankMask(square.Rank6)).South() & empty
 	}
 
 	// Check pawn captures
-	for _, fp := range []int{1, -1} {
-		// Check square is valid
-		toSquare, err := square.NewSquareCheck(f+square.File(fp*inverter), r+square.Rank(1*inverter))
-		if err != nil {
-			continue
-		}
+	captures := bitboard.PawnAttack(pc.IsWhite(), fromSquare) & p.Colored(!pc.IsWhite())
+	if p.EnPassantSquare != square.Square_Invalid {
+		captures |= bitboard.PawnAttack(pc.IsWhite(), fromSquare) & bitboard.New(p.EnPassantSquare)
+	}
 
-		// Check type of move
-		move := GenerateMove(p, fromSquare, toSquare)
-		if move == nil {
-			continue
-		}
+	// Promotions are noisy even without a capture
+	lastRank := bitboard.Rank8 | bitboard.Rank1
+	switch kind {
+	case MoveKind_Noisy:
+		push &= lastRank
+		doublePush = bitboard.Empty
+	case MoveKind_Quiet:
+		push &^= lastRank
+		captures = bitboard.Empty
+	}
 
-		// Add move
-		if move.IsCapture {
-			// Check for promotion
-			_, r := move.To.FileRank()
-			if (r == 8 && move.Piece == piece.Piece_WhitePawn) ||
-				(r == 1 && move.Piece == piece.Piece_BlackPawn) {
-				GeneratePromotionMoves(p, move)
+	for _, targets := range []bitboard.Bitboard{push, doublePush, captures} {
+		for targets != bitboard.Empty {
+			m := newMove(p, fromSquare, targets.PopLSB())
+			m.IsDoublePush = doublePush.Has(m.To)
+
+			// Add move
+			if isPromotionSquare(m) {
+				moves = append(moves, GeneratePromotionMoves(p, m)...)
 			} else {
-				moves = append(moves, move)
+				moves = append(moves, m)
 			}
 		}
 	}
@@ -246,172 +285,146 @@ func GeneratePawnMoves(p *position.Position, fromSquare square.Square) move.Move
 	return moves
 }
 
+func isPromotionSquare(m *move.Move) bool {
+	_, r := m.To.FileRank()
+	return (r == square.Rank8 && m.Piece == piece.Piece_WhitePawn) ||
+		(r == square.Rank1 && m.Piece == piece.Piece_BlackPawn)
+}
+
 func GeneratePromotionMoves(p *position.Position, m *move.Move) move.MoveList {
-	moves := move.MoveList{}
+	moves := make(move.MoveList, 0, len(piece.PromotionPieces))
 
 	inverter := 1
-	if !p.WhitesTurn {
+	if !m.Piece.IsWhite() {
 		inverter = -1
 	}
 
 	for _, promotionPiece := range piece.PromotionPieces {
-		promotionMove := &move.Move{
-			PieceList:  m.PieceList,
-			From:       m.From,
-			To:         m.To,
-			Piece:      m.Piece,
-			PromotedTo: promotionPiece * piece.Piece(inverter),
-		}
-		moves = append(moves, promotionMove)
+		promotionMove := *m
+		promotionMove.PromotedTo = promotionPiece * piece.Piece(inverter)
+		moves = append(moves, &promotionMove)
 	}
 
 	return moves
 }
 
 func GenerateKingMoves(p *position.Position, kingSquare square.Square) move.MoveList {
-	moves := GenerateNoSlideMoves(p, kingSquare, KingMovementPairs)
+	return appendKingMoves(p, move.MoveList{}, kingSquare, MoveKind_All)
+}
 
-	if !(p.WhitesTurn && kingSquare == square.Square_e1) && !(!p.WhitesTurn && kingSquare == square.Square_e8) {
-		// King has moved off starting square
+func appendKingMoves(p *position.Position, moves move.MoveList, kingSquare square.Square, kind MoveKind) move.MoveList {
+	moves = appendTargetMoves(p, moves, kingSquare, bitboard.KingAttacks[kingSquare]&targetsOfKind(p, kind))
+	if kind == MoveKind_Noisy {
 		return moves
 	}
-	if !p.CanCastle(p.WhitesTurn, true) && !p.CanCastle(p.WhitesTurn, false) {
-		// Can't castle either way
+
+	king := p.PieceAt(kingSquare)
+	if !(king.IsWhite() && kingSquare == square.Square_e1) && !(king.IsBlack() && kingSquare == square.Square_e8) {
+		// King has moved off starting square
 		return moves
 	}
 
-	// Castling moves
-	f, r := kingSquare.FileRank()
-	for _, pair := range []MovementPair{{2, 0}, {-2, 0}} {
-		// Is valid square
-		toSquare, err := square.NewSquareCheck(f+square.File(pair.FP), r+square.Rank(pair.RP))
-		if err != nil {
-			break
+	// Castling moves, checks on the king's path are left to GenerateMoves
+	_, r := kingSquare.FileRank()
+	rook := piece.Piece_WhiteRook
+	if king.IsBlack() {
+		rook = piece.Piece_BlackRook
+	}
+	for _, isShort := range []bool{true, false} {
+		if !p.CanCastle(king.IsWhite(), isShort) {
+			continue
 		}
 
-		// Create move
-		move := GenerateMove(p, kingSquare, toSquare)
-		if move == nil {
-			break
+		// Rook must still be in the corner
+		rookFile, toFile := square.FileA, square.FileC
+		if isShort {
+			rookFile, toFile = square.FileH, square.FileG
+		}
+		rookSquare := square.NewSquare(rookFile, r)
+		if p.PieceAt(rookSquare) != rook {
+			continue
 		}
 
-		// Add move
-		moves = append(moves, move)
-		if move.IsCapture {
-			break
+		// Squares between king and rook must be empty
+		if bitboard.Between(kingSquare, rookSquare)&p.Occupied() != bitboard.Empty {
+			continue
 		}
+
+		// Add move
+		m := newMove(p, kingSquare, square.NewSquare(toFile, r))
+		m.IsCastling = true
+		moves = append(moves, m)
 	}
 
 	return moves
 }
 
 func GenerateKnightMoves(p *position.Position, fromSquare square.Square) move.MoveList {
-	return GenerateNoSlideMoves(p, fromSquare, KnightMovementPairs)
+	return GenerateNoSlideMoves(p, fromSquare, &bitboard.KnightAttacks)
 }
 
 func GenerateBishopMoves(p *position.Position, fromSquare square.Square) move.MoveList {
-	return GenerateSlideMoves(p, fromSquare, BishopMovementPairs)
+	return GenerateSlideMoves(p, fromSquare, bitboard.BishopAttacks)
 }
 
 func GenerateRookMoves(p *position.Position, fromSquare square.Square) move.MoveList {
-	return GenerateSlideMoves(p, fromSquare, RookMovementPairs)
+	return GenerateSlideMoves(p, fromSquare, bitboard.RookAttacks)
 }
 
 func GenerateQueenMoves(p *position.Position, fromSquare square.Square) move.MoveList {
-	return GenerateSlideMoves(p, fromSquare, QueenMovementPairs)
+	return GenerateSlideMoves(p, fromSquare, bitboard.QueenAttacks)
 }
 
 // ========================= Movement Moves ====================
 
-var (
-	KnightMovementPairs   = []MovementPair{{1, 2}, {2, 1}, {1, -2}, {2, -1}, {-1, 2}, {-2, 1}, {-1, -2}, {-2, -1}}
-	BishopMovementPairs   = []MovementPair{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
-	RookMovementPairs     = []MovementPair{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
-	QueenMovementPairs    = append(BishopMovementPairs, RookMovementPairs...)
-	KingMovementPairs     = QueenMovementPairs
-	KingCastlingMovements = []MovementPair{{2, 0}, {-2, 0}}
-)
+// SlideAttacks returns the squares a sliding piece attacks given the occupied squares.
+type SlideAttacks func(s square.Square, occupied bitboard.Bitboard) bitboard.Bitboard
 
-type MovementPair struct{ RP, FP int }
-
-func GenerateNoSlideMoves(p *position.Position, fromSquare square.Square, pairs []MovementPair) move.MoveList {
-	return GenerateMovementMoves(p, fromSquare, pairs, 1)
+func GenerateNoSlideMoves(p *position.Position, fromSquare square.Square, attacks *[64]bitboard.Bitboard) move.MoveList {
+	return appendTargetMoves(p, move.MoveList{}, fromSquare, attacks[fromSquare])
 }
 
-func GenerateSlideMoves(p *position.Position, fromSquare square.Square, pairs []MovementPair) move.MoveList {
-	return GenerateMovementMoves(p, fromSquare, pairs, 7)
+func GenerateSlideMoves(p *position.Position, fromSquare square.Square, attacks SlideAttacks) move.MoveList {
+	return appendTargetMoves(p, move.MoveList{}, fromSquare, attacks(fromSquare, p.Occupied()))
 }
 
-func GenerateMovementMoves(p *position.Position, fromSquare square.Square, pairs []MovementPair, slideCount int) move.MoveList {
-	moves := move.MoveList{}
-
-	// Movements
-	f, r := fromSquare.FileRank()
-	for _, pair := range pairs {
-		for i := 1; i <= slideCount; i++ {
-			// Is valid square
-			toSquare, err := square.NewSquareCheck(f+square.File(pair.FP*i), r+square.Rank(pair.RP*i))
-			if err != nil {
-				break
-			}
-
-			// Create move
-			move := GenerateMove(p, fromSquare, toSquare)
-			if move == nil {
-				break
-			}
-
-			// Add move
-			moves = append(moves, move)
-			if move.IsCapture {
-				break
-			}
+// appendTargetMoves adds a move to every target square not holding a piece of the moving color.
+func appendTargetMoves(p *position.Position, moves move.MoveList, fromSquare square.Square, targets bitboard.Bitboard) move.MoveList {
+	targets &^= p.Colored(p.PieceAt(fromSquare).IsWhite())
+
+	// Allocate the moves of one piece together
+	batch := make([]move.Move, targets.Count())
+	for i := range batch {
+		toSquare := targets.PopLSB()
+		batch[i] = move.Move{
+			From:      fromSquare,
+			To:        toSquare,
+			Piece:     p.PieceAt(fromSquare),
+			IsCapture: !p.PieceAt(toSquare).IsEmpty(),
 		}
+		moves = append(moves, &batch[i])
 	}
-
 	return moves
 }
 
 // ==================== Basic Generate Move ====================
 
 func GenerateMove(p *position.Position, fromSquare, toSquare square.Square) *move.Move {
-	// inverter used to determine same/opposite color
-	inverter := func() int {
-		if p.WhitesTurn {
-			return 1
-		} else {
-			return -1
-		}
-	}()
-
-	pc := p.PieceAt(toSquare)
-	if pc*piece.Piece(inverter) > 0 {
+	fromPiece := p.PieceAt(fromSquare)
+	if p.Colored(fromPiece.IsWhite()).Has(toSquare) {
 		// Same color piece
 		return nil
 	}
+	return newMove(p, fromSquare, toSquare)
+}
 
+func newMove(p *position.Position, fromSquare, toSquare square.Square) *move.Move {
 	fromPiece := p.PieceAt(fromSquare)
-
-	// Create move
-	move := &move.Move{
-		PieceList: p.PieceList,
-		From:      fromSquare,
-		To:        toSquare,
-		Piece:     fromPiece,
-	}
-
-	if pc*piece.Piece(inverter) < 0 {
-		// Opposite color piece
-		move.IsCapture = true
-		return move
+	return &move.Move{
+		From:  fromSquare,
+		To:    toSquare,
+		Piece: fromPiece,
+		// Opposite color piece or en passant
+		IsCapture: !p.PieceAt(toSquare).IsEmpty() || (fromPiece.IsPawn() && toSquare == p.EnPassantSquare),
 	}
-	// Empty Square
-
-	// Check for EnPassant
-	if fromPiece.IsPawn() && toSquare == p.EnPassantSquare {
-		move.IsCapture = true
-		return move
-	}
-
-	return move
 }
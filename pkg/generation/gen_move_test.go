@@ -4,10 +4,14 @@ import (
 	"fmt"
 	"testing"
 
+	"gochess/pkg/bitboard"
 	"gochess/pkg/generation"
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/piece"
 	"gochess/pkg/notation/position"
 	"gochess/pkg/notation/square"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -36,7 +40,7 @@ func BenchmarkGenerateSudoLegalMoves(b *testing.B) {
 	})
 	b.Run("GenerateNoSlideMoves", func(b *testing.B) {
 		for n := 0; n < b.N; n++ {
-			_ = generation.GenerateNoSlideMoves(startingBoard, square.Square_b3, generation.KnightMovementPairs)
+			_ = generation.GenerateNoSlideMoves(startingBoard, square.Square_b1, &bitboard.KnightAttacks)
 		}
 	})
 	b.Run("GenerateMove", func(b *testing.B) {
@@ -45,3 +49,60 @@ func BenchmarkGenerateSudoLegalMoves(b *testing.B) {
 		}
 	})
 }
+
+func TestGenerateMoves(t *testing.T) {
+	tests := []struct {
+		name  string
+		fen   position.FEN
+		moves []move.PCN
+	}{
+		{"Rook Pinned On File", "4r1k1/8/8/8/8/8/4R3/4K3 w - - 0 1",
+			[]move.PCN{"e1d1", "e1d2", "e1f1", "e1f2", "e2e3", "e2e4", "e2e5", "e2e6", "e2e7", "e2e8"}},
+		{"Bishop Pinned On Rank", "8/8/8/r2BK3/8/8/8/6k1 w - - 0 1",
+			[]move.PCN{"e5d4", "e5e4", "e5f4", "e5d6", "e5e6", "e5f6", "e5f5"}},
+		{"Rook Check On Rank", "8/8/8/8/8/8/1N6/r3K2k w - - 0 1",
+			[]move.PCN{"e1e2", "e1f2", "e1d2", "b2d1"}},
+		{"Double Check", "4k3/8/8/8/8/5n2/8/4r1K1 w - - 0 1",
+			[]move.PCN{"g1f2", "g1g2"}},
+		{"En Passant Uncovers King", "8/8/8/KPp4r/8/8/8/7k w - c6 0 1",
+			[]move.PCN{"a5a4", "a5a6", "a5b6", "b5b6"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := position.NewPosition(test.fen)
+			require.NoError(t, err)
+			moves := generation.GenerateMoves(p)
+			pcns := make([]move.PCN, 0, len(moves))
+			for _, m := range moves {
+				pcns = append(pcns, m.PCN())
+			}
+			assert.ElementsMatch(t, test.moves, pcns)
+		})
+	}
+}
+
+func TestGenerateMovesOfKind(t *testing.T) {
+	for _, test := range perftTests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := position.NewPosition(test.fen)
+			require.NoError(t, err)
+
+			// Walk two plies, noisy and quiet moves must split the legal moves
+			for _, m := range generation.GenerateMoves(p) {
+				p.Make(m.From, m.To, m.PromotedTo)
+				all := generation.GenerateMoves(p)
+				noisy := generation.GenerateMovesOfKind(p, generation.MoveKind_Noisy)
+				quiet := generation.GenerateMovesOfKind(p, generation.MoveKind_Quiet)
+				for _, n := range noisy {
+					assert.True(t, n.IsCapture || n.PromotedTo != piece.Piece_None, n.PCN())
+				}
+				for _, q := range quiet {
+					assert.False(t, q.IsCapture || q.PromotedTo != piece.Piece_None, q.PCN())
+				}
+				assert.ElementsMatch(t, all, append(noisy, quiet...), p.FEN())
+				p.Unmake()
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package generation_test
+
+import (
+	"slices"
+	"testing"
+
+	"gochess/pkg/generation"
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/position"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeMove(t *testing.T) {
+	tests := []struct {
+		name     string
+		fen      position.FEN
+		moves    []move.PCN
+		expected position.FEN
+	}{
+		{"Double Push", position.StartingFEN, []move.PCN{"e2e4"},
+			"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1"},
+		{"Black Pawn Resets Halfmove", "4k3/4p3/8/8/8/8/8/4K3 b - - 5 10", []move.PCN{"e7e6"},
+			"4k3/8/4p3/8/8/8/8/4K3 w - - 0 11"},
+		{"En Passant", position.StartingFEN, []move.PCN{"e2e4", "a7a6", "e4e5", "d7d5", "e5d6"},
+			"rnbqkbnr/1pp1pppp/p2P4/8/8/8/PPPP1PPP/RNBQKBNR b KQkq - 0 3"},
+		{"Short Castling", "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 3 1", []move.PCN{"e1g1"},
+			"r3k2r/8/8/8/8/8/8/R4RK1 b kq - 4 1"},
+		{"Long Castling", "r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 3 1", []move.PCN{"e8c8"},
+			"2kr3r/8/8/8/8/8/8/R3K2R w KQ - 4 2"},
+		{"Rook Move", "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", []move.PCN{"h1h2"},
+			"r3k2r/8/8/8/8/8/7R/R3K3 b Qkq - 1 1"},
+		{"Rook Capture", "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", []move.PCN{"a1a8"},
+			"R3k2r/8/8/8/8/8/8/4K2R b Kk - 0 1"},
+		{"Promotion", "8/P7/8/8/8/8/8/k6K w - - 0 1", []move.PCN{"a7a8n"},
+			"N7/8/8/8/8/8/8/k6K b - - 0 1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := position.NewPosition(test.fen)
+			require.NoError(t, err)
+			for _, pcn := range test.moves {
+				moves := generation.GenerateMoves(p)
+				i := slices.IndexFunc(moves, func(m *move.Move) bool { return m.PCN() == pcn })
+				require.GreaterOrEqual(t, i, 0, "move %s not generated", pcn)
+				p = generation.MakeMove(p, *moves[i])
+			}
+			assert.Equal(t, test.expected, p.FEN())
+		})
+	}
+}
+
+func TestMakeUnmake(t *testing.T) {
+	var walk func(t *testing.T, p *position.Position, depth int)
+	walk = func(t *testing.T, p *position.Position, depth int) {
+		if depth == 0 {
+			return
+		}
+		before, beforeHash := p.FEN(), p.Hash
+		for _, m := range generation.GenerateMoves(p) {
+			after := generation.MakeMove(p, *m)
+			require.Equal(t, before, p.FEN(), "MakeMove changed the position")
+
+			p.Make(m.From, m.To, m.PromotedTo)
+			require.Equal(t, after.FEN(), p.FEN(), "Make %s", m.PCN())
+			fromFEN, err := position.NewPosition(p.FEN())
+			require.NoError(t, err)
+			require.Equal(t, fromFEN.PieceBoards, p.PieceBoards, "Make %s", m.PCN())
+			require.Equal(t, fromFEN.ColorBoards, p.ColorBoards, "Make %s", m.PCN())
+			require.Equal(t, fromFEN.Hash, p.Hash, "Make %s", m.PCN())
+			walk(t, p, depth-1)
+			p.Unmake()
+			require.Equal(t, before, p.FEN(), "Unmake %s", m.PCN())
+			require.Equal(t, beforeHash, p.Hash, "Unmake %s", m.PCN())
+		}
+	}
+
+	for _, test := range perftTests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := position.NewPosition(test.fen)
+			require.NoError(t, err)
+			walk(t, p, 2)
+		})
+	}
+}
+
+func BenchmarkMakeMove(b *testing.B) {
+	startingPosition, err := position.NewPosition(position.StartingFEN)
+	require.NoError(b, err)
+	m := *generation.GenerateMoves(startingPosition)[0]
+	b.Run("MakeMove", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			_ = generation.MakeMove(startingPosition, m)
+		}
+	})
+	b.Run("MakeUnmake", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			startingPosition.Make(m.From, m.To, m.PromotedTo)
+			startingPosition.Unmake()
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package generation
+
+import (
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/position"
+)
+
+// Perft counts the leaf nodes of the legal move tree of the given depth.
+func Perft(p *position.Position, depth int) int {
+	if depth <= 0 {
+		return 1
+	}
+
+	moves := GenerateMoves(p)
+	if depth == 1 {
+		// Bulk count the leaf moves
+		return len(moves)
+	}
+
+	var nodes int
+	for _, m := range moves {
+		p.Make(m.From, m.To, m.PromotedTo)
+		nodes += Perft(p, depth-1)
+		p.Unmake()
+	}
+	return nodes
+}
+
+// PerftDivide counts the leaf nodes of the legal move tree of the given depth
+// below each root move, keyed by the move in Pure Coordinate Notation.
+func PerftDivide(p *position.Position, depth int) map[move.PCN]int {
+	divide := map[move.PCN]int{}
+	if depth <= 0 {
+		return divide
+	}
+
+	for _, m := range GenerateMoves(p) {
+		p.Make(m.From, m.To, m.PromotedTo)
+		divide[m.PCN()] = Perft(p, depth-1)
+		p.Unmake()
+	}
+	return divide
+}
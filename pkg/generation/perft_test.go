@@ -0,0 +1,71 @@
+package generation_test
+
+import (
+	"fmt"
+	"testing"
+
+	"gochess/pkg/generation"
+	"gochess/pkg/notation/position"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Reference counts from https://www.chessprogramming.org/Perft_Results
+var perftTests = []struct {
+	name  string
+	fen   position.FEN
+	nodes []int
+}{
+	{"Initial Position", position.StartingFEN,
+		[]int{20, 400, 8902, 197281}},
+	{"Kiwipete", "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+		[]int{48, 2039, 97862}},
+	{"Position 3", "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+		[]int{14, 191, 2812, 43238}},
+	{"Position 4", "r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1",
+		[]int{6, 264, 9467}},
+	{"Position 4 Mirrored", "r2q1rk1/pP1p2pp/Q4n2/bbp1p3/Np6/1B3NBn/pPPP1PPP/R3K2R b KQ - 0 1",
+		[]int{6, 264, 9467}},
+	{"Position 5", "rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8",
+		[]int{44, 1486, 62379}},
+	{"Position 6", "r4rk1/1pp1qppp/p1np1n2/2b1p1B1/2B1P1b1/P1NP1N2/1PP1QPPP/R4RK1 w - - 0 10",
+		[]int{46, 2079, 89890}},
+}
+
+func TestPerft(t *testing.T) {
+	for _, test := range perftTests {
+		p, err := position.NewPosition(test.fen)
+		require.NoError(t, err)
+		for i, nodes := range test.nodes {
+			depth := i + 1
+			t.Run(fmt.Sprintf("%s:%d", test.name, depth), func(t *testing.T) {
+				assert.Equal(t, nodes, generation.Perft(p, depth))
+			})
+		}
+	}
+}
+
+func TestPerftDivide(t *testing.T) {
+	startingPosition, err := position.NewPosition(position.StartingFEN)
+	require.NoError(t, err)
+
+	divide := generation.PerftDivide(startingPosition, 3)
+	assert.Len(t, divide, len(generation.GenerateMoves(startingPosition)))
+
+	var total int
+	for _, nodes := range divide {
+		total += nodes
+	}
+	assert.Equal(t, generation.Perft(startingPosition, 3), total)
+
+	assert.Empty(t, generation.PerftDivide(startingPosition, 0))
+}
+
+func BenchmarkPerft(b *testing.B) {
+	startingPosition, err := position.NewPosition(position.StartingFEN)
+	require.NoError(b, err)
+	for n := 0; n < b.N; n++ {
+		_ = generation.Perft(startingPosition, 3)
+	}
+}
@@ -0,0 +1,92 @@
+package generation
+
+import (
+	"gochess/pkg/bitboard"
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/piece"
+	"gochess/pkg/notation/position"
+	"gochess/pkg/notation/square"
+)
+
+// SEEValues are the piece values in centipawns used by SEE, indexed by piece type.
+var SEEValues = [...]int{
+	piece.Piece_None:   0,
+	piece.Piece_Pawn:   100,
+	piece.Piece_Knight: 300,
+	piece.Piece_Bishop: 300,
+	piece.Piece_Rook:   500,
+	piece.Piece_Queen:  900,
+	piece.Piece_King:   20000,
+}
+
+// seeOrder is the order attackers join an exchange, least valuable first.
+var seeOrder = [...]piece.Piece{
+	piece.Piece_Pawn, piece.Piece_Knight, piece.Piece_Bishop,
+	piece.Piece_Rook, piece.Piece_Queen, piece.Piece_King,
+}
+
+// GenerateCaptures returns the legal captures and promotions, unsorted.
+func GenerateCaptures(p *position.Position) move.MoveList {
+	return GenerateMovesOfKind(p, MoveKind_Noisy)
+}
+
+// SEE returns the material won in centipawns by the move once the exchange of pieces
+// on its target square settles, each side recapturing with its least valuable attacker
+// or stopping when it would lose. Sliders behind the capturing pieces join as x-rays.
+func SEE(p *position.Position, m move.Move) int {
+	var gain [32]int
+	occupied := p.Occupied()
+
+	// The first capture, removing the pawn taken en passant
+	captured := p.PieceAt(m.To)
+	if m.Piece.IsPawn() && m.To == p.EnPassantSquare {
+		toF, _ := m.To.FileRank()
+		_, fromR := m.From.FileRank()
+		captured = -m.Piece
+		occupied = occupied.Clear(square.NewSquare(toF, fromR))
+	}
+	gain[0] = SEEValues[captured.Abs()]
+	onSquare := m.Piece.Abs()
+	if m.PromotedTo != piece.Piece_None {
+		gain[0] += SEEValues[m.PromotedTo.Abs()] - SEEValues[piece.Piece_Pawn]
+		onSquare = m.PromotedTo.Abs()
+	}
+	occupied = occupied.Clear(m.From)
+	isWhite := !m.Piece.IsWhite()
+
+	// Play the recaptures
+	d := 0
+	for d+1 < len(gain) {
+		attackers := p.AttackersTo(m.To, occupied) & occupied & p.Colored(isWhite)
+		if attackers == bitboard.Empty {
+			break
+		}
+
+		// Find the least valuable attacker
+		var from square.Square
+		var pc piece.Piece
+		for _, pc = range seeOrder {
+			if b := attackers & p.PiecesOfType(pc); b != bitboard.Empty {
+				from = b.LSB()
+				break
+			}
+		}
+
+		// The king can't recapture a defended piece
+		if pc == piece.Piece_King && p.AttackersTo(m.To, occupied.Clear(from))&occupied&p.Colored(!isWhite) != bitboard.Empty {
+			break
+		}
+
+		d++
+		gain[d] = SEEValues[onSquare] - gain[d-1]
+		onSquare = pc
+		occupied = occupied.Clear(from)
+		isWhite = !isWhite
+	}
+
+	// Each side only recaptures when it doesn't lose by it
+	for ; d > 0; d-- {
+		gain[d-1] = -max(-gain[d-1], gain[d])
+	}
+	return gain[0]
+}
@@ -0,0 +1,58 @@
+package generation_test
+
+import (
+	"testing"
+
+	"gochess/pkg/generation"
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/position"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSEE(t *testing.T) {
+	tests := []struct {
+		name     string
+		fen      position.FEN
+		pcn      move.PCN
+		expected int
+	}{
+		{"Undefended Pawn", "1k1r4/1pp4p/p7/4p3/8/P5P1/1PP4P/2K1R3 w - - 0 1", "e1e5", 100},
+		{"Knight For Pawn", "1k1r3q/1ppn3p/p4b2/4p3/8/P2N2P1/1PP1R1BP/2K1Q3 w - - 0 1", "d3e5", -200},
+		{"Queen For Defended Pawn", "4k3/8/4p3/3p4/8/8/8/3QK3 w - - 0 1", "d1d5", -800},
+		{"Undefended Bishop", "4k3/8/2n5/3b4/8/8/6B1/4K3 w - - 0 1", "g2d5", 300},
+		{"Quiet Move", position.StartingFEN, "g1f3", 0},
+		{"Hanging Quiet Move", "4k3/8/8/3p4/8/8/8/2N1K3 w - - 0 1", "c1b3", 0},
+		{"Rook X-Ray", "3r2k1/8/8/3p4/8/8/3R4/3RK3 w - - 0 1", "d2d5", 100},
+		{"Rook X-Ray Against Queen Battery", "3q2k1/3r4/8/3p4/8/8/3R4/3RK3 w - - 0 1", "d2d5", -400},
+		{"Bishop Behind Queen", "4k3/8/1n6/8/3Q4/8/5B2/4K3 w - - 0 1", "d4b6", 300},
+		{"Defended By Bishop X-Ray", "4k3/6b1/5b2/4p3/8/8/1Q6/B3K3 w - - 0 1", "b2e5", -800},
+		{"Pawn Through Queen X-Ray", "4k3/8/8/3r4/4P3/5Q2/8/4K3 w - - 0 1", "e4d5", 500},
+		{"King Can't Recapture Defended", "8/8/8/5k2/4p3/8/3N1N2/4K3 w - - 0 1", "d2e4", 100},
+		{"King Recaptures", "8/8/8/5k2/4p3/8/3N4/4K3 w - - 0 1", "d2e4", -200},
+		{"En Passant", "4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 1", "e5d6", 100},
+		{"Promotion", "4k3/1P6/8/8/8/8/8/4K3 w - - 0 1", "b7b8q", 800},
+		{"Defended Promotion", "1rk5/P7/8/8/8/8/8/4K3 w - - 0 1", "a7b8q", 400},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := position.NewPosition(test.fen)
+			require.NoError(t, err)
+			m, err := move.NewMoveFromPCN(p, test.pcn)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, generation.SEE(p, m))
+		})
+	}
+}
+
+func TestGenerateCaptures(t *testing.T) {
+	p, err := position.NewPosition("1r2k3/P7/8/3pP3/8/8/8/4K3 w - d6 0 1")
+	require.NoError(t, err)
+
+	var pcns []move.PCN
+	for _, m := range generation.GenerateCaptures(p) {
+		pcns = append(pcns, m.PCN())
+	}
+	assert.ElementsMatch(t, []move.PCN{"a7a8q", "a7a8r", "a7a8b", "a7a8n", "a7b8q", "a7b8r", "a7b8b", "a7b8n", "e5d6"}, pcns)
+}
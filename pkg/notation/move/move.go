@@ -2,13 +2,21 @@ package move
 
 import (
 	"cmp"
+	"errors"
 	"fmt"
+	"regexp"
 	"slices"
+	"strings"
 
+	"gochess/pkg/bitboard"
 	"gochess/pkg/notation/piece"
+	"gochess/pkg/notation/position"
 	"gochess/pkg/notation/square"
 )
 
+// ErrSyntax is wrapped by the errors of moves that don't parse in the notation.
+var ErrSyntax = errors.New("syntax error")
+
 // ==================== Move List ====================
 
 type MoveList []*Move
@@ -24,13 +32,26 @@ func (m MoveList) Sort() {
 		if pieceCmp != 0 {
 			return pieceCmp
 		}
-		if fromCmp := cmp.Compare(a.From.String(), b.From.String()); fromCmp != 0 {
+		if fromCmp := compareSquares(a.From, b.From); fromCmp != 0 {
 			return fromCmp
 		}
-		return cmp.Compare(a.To.String(), b.To.String())
+		if toCmp := compareSquares(a.To, b.To); toCmp != 0 {
+			return toCmp
+		}
+		return cmp.Compare(a.PromotedTo.Abs(), b.PromotedTo.Abs())
 	})
 }
 
+// compareSquares orders squares the same way as their names.
+func compareSquares(a, b square.Square) int {
+	aF, aR := a.FileRank()
+	bF, bR := b.FileRank()
+	if fileCmp := cmp.Compare(aF, bF); fileCmp != 0 {
+		return fileCmp
+	}
+	return cmp.Compare(aR, bR)
+}
+
 func (m MoveList) FindMovesFrom(fromSquare square.Square) MoveList {
 	moves := make(MoveList, 0, len(m))
 	for _, move := range m {
@@ -71,13 +92,62 @@ type Move struct {
 	IsCapture    bool
 	IsDoublePush bool
 	IsCastling   bool
-	PieceList    []piece.Piece
 }
 
 func (m Move) String() string {
 	return string(m.LAN())
 }
 
+// newMove builds the move from one square to another, checking it is legal in the position.
+func newMove(p *position.Position, from, to square.Square, promotedTo piece.Piece) (Move, error) {
+	if err := p.ValidateMove(from, to, promotedTo); err != nil {
+		return Move{}, err
+	}
+
+	pc := p.PieceAt(from)
+	fromF, fromR := from.FileRank()
+	toF, toR := to.FileRank()
+	return Move{
+		From:         from,
+		To:           to,
+		Piece:        pc,
+		PromotedTo:   promotedTo,
+		IsCapture:    !p.PieceAt(to).IsEmpty() || (pc.IsPawn() && to == p.EnPassantSquare),
+		IsDoublePush: pc.IsPawn() && (toR-fromR == 2 || fromR-toR == 2),
+		IsCastling:   pc.IsKing() && (toF-fromF == 2 || fromF-toF == 2),
+	}, nil
+}
+
+// newPromotedTo returns the piece of the side to move for a promotion symbol, or none if empty.
+func newPromotedTo(p *position.Position, symbol string) (piece.Piece, error) {
+	if symbol == "" {
+		return piece.Piece_None, nil
+	}
+	pc, err := piece.PieceChar(strings.ToUpper(symbol)[0]).Val()
+	if err != nil {
+		return piece.Piece_None, fmt.Errorf("invalid promotion piece %q", symbol)
+	}
+	if !p.WhitesTurn {
+		pc = -pc
+	}
+	return pc, nil
+}
+
+// newCastlingMove builds the castling move of the side to move.
+func newCastlingMove(p *position.Position, isShort bool) (Move, error) {
+	from, to := square.Square_e1, square.Square_c1
+	if isShort {
+		to = square.Square_g1
+	}
+	if !p.WhitesTurn {
+		from, to = from+56, to+56
+	}
+	if !p.PieceAt(from).IsKing() {
+		return Move{}, fmt.Errorf("no king on %s to castle with", from)
+	}
+	return newMove(p, from, to, piece.Piece_None)
+}
+
 // PCN - Pure Coordinate Notation
 // <move descriptor> ::= <from square><to square>[<promoted to>]
 // <square>        ::= <file letter><rank number>
@@ -86,14 +156,32 @@ func (m Move) String() string {
 // <promoted to>   ::= 'q'|'r'|'b'|'n'
 type PCN string
 
-func NewMoveFromPCN(pcn PCN) (Move, error) {
-	return Move{}, nil
+var pcnRegExp = regexp.MustCompile("^([a-h][1-8])([a-h][1-8])([qrbnQRBN])?$")
+
+func NewMoveFromPCN(p *position.Position, pcn PCN) (Move, error) {
+	submatches := pcnRegExp.FindStringSubmatch(string(pcn))
+	if submatches == nil {
+		return Move{}, fmt.Errorf("invalid pcn %q: %w", pcn, ErrSyntax)
+	}
+
+	from, _ := square.NewSquareFromString(submatches[1])
+	to, _ := square.NewSquareFromString(submatches[2])
+	promotedTo, err := newPromotedTo(p, submatches[3])
+	if err != nil {
+		return Move{}, fmt.Errorf("invalid pcn %q: %w", pcn, err)
+	}
+
+	m, err := newMove(p, from, to, promotedTo)
+	if err != nil {
+		return Move{}, fmt.Errorf("illegal move %s: %w", pcn, err)
+	}
+	return m, nil
 }
 
 func (m Move) PCN() PCN {
 	promotedToStr := ""
 	if m.PromotedTo != piece.Piece_None {
-		promotedToStr = m.PromotedTo.Symbol()
+		promotedToStr = strings.ToLower(m.PromotedTo.Symbol())
 	}
 	return PCN(fmt.Sprintf("%s%s%s", m.From, m.To, promotedToStr))
 }
@@ -104,8 +192,50 @@ func (m Move) PCN() PCN {
 // <Piece symbol> ::= 'N' | 'B' | 'R' | 'Q' | 'K'
 type LAN string
 
-func NewMoveFromLAN(lan LAN) (Move, error) {
-	return Move{}, nil
+var lanRegExp = regexp.MustCompile("^(?:(O-O-O|0-0-0)|(O-O|0-0)|([NBRQK])?([a-h][1-8])([-x])?([a-h][1-8])(?:=?([QRBN]))?)[+#]?[!?]*$")
+
+func NewMoveFromLAN(p *position.Position, lan LAN) (Move, error) {
+	submatches := lanRegExp.FindStringSubmatch(string(lan))
+	if submatches == nil {
+		return Move{}, fmt.Errorf("invalid lan %q: %w", lan, ErrSyntax)
+	}
+
+	// Castling
+	if submatches[1] != "" || submatches[2] != "" {
+		m, err := newCastlingMove(p, submatches[2] != "")
+		if err != nil {
+			return Move{}, fmt.Errorf("illegal move %s: %w", lan, err)
+		}
+		return m, nil
+	}
+
+	from, _ := square.NewSquareFromString(submatches[4])
+	to, _ := square.NewSquareFromString(submatches[6])
+	promotedTo, err := newPromotedTo(p, submatches[7])
+	if err != nil {
+		return Move{}, fmt.Errorf("invalid lan %q: %w", lan, err)
+	}
+
+	m, err := newMove(p, from, to, promotedTo)
+	if err != nil {
+		return Move{}, fmt.Errorf("illegal move %s: %w", lan, err)
+	}
+
+	// Check the written piece and capture marker match the position
+	symbol := submatches[3]
+	if symbol == "" {
+		symbol = string(piece.PieceStr_Pawn)
+	}
+	if m.Piece.Symbol() != symbol {
+		return Move{}, fmt.Errorf("illegal move %s: piece on %s is %s not %s", lan, from, m.Piece.Symbol(), symbol)
+	}
+	if submatches[5] == "x" && !m.IsCapture {
+		return Move{}, fmt.Errorf("illegal move %s: nothing to capture on %s", lan, to)
+	}
+	if submatches[5] == "-" && m.IsCapture {
+		return Move{}, fmt.Errorf("illegal move %s: move to %s is a capture", lan, to)
+	}
+	return m, nil
 }
 
 func (m Move) LAN() LAN {
@@ -125,34 +255,194 @@ func (m Move) LAN() LAN {
 }
 
 // SAN - Standard Algebraic Notation
-// <SAN move descriptor piece moves>   ::= <Piece symbol>[<from file>|<from rank>|<from square>]['x']<to square>
-// <SAN move descriptor pawn captures> ::= <from file>[<from rank>] 'x' <to square>[<promoted to>]
-// <SAN move descriptor pawn push>     ::= <to square>[<promoted to>]
+// <SAN move descriptor piece moves>   ::= <Piece symbol>[<from file>|<from rank>|<from square>]['x']<to square>[<suffix>]
+// <SAN move descriptor pawn captures> ::= <from file> 'x' <to square>['=' <promoted to>][<suffix>]
+// <SAN move descriptor pawn push>     ::= <to square>['=' <promoted to>][<suffix>]
+// <SAN move descriptor castling>      ::= 'O-O' | 'O-O-O'
+// <suffix>                            ::= '+' | '#'
 type SAN string
 
-func NewMoveFromSAN(san SAN) (Move, error) {
-	return Move{}, nil
+var sanRegExp = regexp.MustCompile("^(?:(O-O-O|0-0-0)|(O-O|0-0)|([NBRQK])?([a-h])?([1-8])?(x)?([a-h][1-8])(?:=?([QRBN]))?)[+#]?[!?]*$")
+
+func NewMoveFromSAN(p *position.Position, san SAN) (Move, error) {
+	submatches := sanRegExp.FindStringSubmatch(string(san))
+	if submatches == nil {
+		return Move{}, fmt.Errorf("invalid san %q: %w", san, ErrSyntax)
+	}
+
+	// Castling
+	if submatches[1] != "" || submatches[2] != "" {
+		m, err := newCastlingMove(p, submatches[2] != "")
+		if err != nil {
+			return Move{}, fmt.Errorf("illegal move %s: %w", san, err)
+		}
+		return m, nil
+	}
+
+	// Find the moving piece
+	symbol := submatches[3]
+	if symbol == "" {
+		symbol = string(piece.PieceStr_Pawn)
+	}
+	pc, _ := piece.PieceChar(symbol[0]).Val()
+	if !p.WhitesTurn {
+		pc = -pc
+	}
+	to, _ := square.NewSquareFromString(submatches[7])
+	promotedTo, err := newPromotedTo(p, submatches[8])
+	if err != nil {
+		return Move{}, fmt.Errorf("invalid san %q: %w", san, err)
+	}
+
+	// Pawns without a from file push along the file of the target square
+	fromSquares := p.Pieces(pc)
+	if submatches[4] != "" {
+		f, _ := square.NewFileFromString(submatches[4])
+		fromSquares &= bitboard.FileMask(f)
+	} else if pc.IsPawn() {
+		f, _ := to.FileRank()
+		fromSquares &= bitboard.FileMask(f)
+	}
+	if submatches[5] != "" {
+		r, _ := square.NewRankFromString(submatches[5])
+		fromSquares &= bitboard.RankMask(r)
+	}
+
+	// Find the one legal move matching the description
+	var candidates []Move
+	var lastErr error
+	tried := fromSquares.Count()
+	for fromSquares != bitboard.Empty {
+		m, err := newMove(p, fromSquares.PopLSB(), to, promotedTo)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		candidates = append(candidates, m)
+	}
+	switch len(candidates) {
+	case 0:
+		if tried == 1 {
+			return Move{}, fmt.Errorf("illegal move %s: %w", san, lastErr)
+		}
+		return Move{}, fmt.Errorf("illegal move %s: no %s can move to %s", san, symbol, to)
+	case 1:
+	default:
+		froms := make([]string, 0, len(candidates))
+		for _, m := range candidates {
+			froms = append(froms, m.From.String())
+		}
+		return Move{}, fmt.Errorf("ambiguous move %s: %s on %s can all move to %s",
+			san, symbol, strings.Join(froms, ", "), to)
+	}
+
+	m := candidates[0]
+	if submatches[6] == "x" && !m.IsCapture {
+		return Move{}, fmt.Errorf("illegal move %s: nothing to capture on %s", san, to)
+	}
+	return m, nil
 }
 
-func (m Move) SAN() SAN {
-	if !m.Piece.IsPawn() {
-		capStr := ""
+// SAN returns the move in Standard Algebraic Notation. The position is the one
+// before the move, it is needed to disambiguate and to add check and mate suffixes.
+func (m Move) SAN(p *position.Position) SAN {
+	var sb strings.Builder
+	toF, _ := m.To.FileRank()
+	fromF, fromR := m.From.FileRank()
+
+	switch {
+	case m.IsCastling && toF == square.FileG:
+		sb.WriteString("O-O")
+	case m.IsCastling:
+		sb.WriteString("O-O-O")
+	case m.Piece.IsPawn():
 		if m.IsCapture {
-			capStr = "x"
+			sb.WriteString(fromF.String() + "x")
 		}
-		fromStr := ""
-		// TODO solve ambiguities
-		return SAN(fmt.Sprintf("%s%s%s%s", m.Piece.Symbol(), fromStr, capStr, m.To))
-	} else {
-		promotedToStr := ""
+		sb.WriteString(m.To.String())
 		if m.PromotedTo != piece.Piece_None {
-			promotedToStr = m.PromotedTo.Symbol()
+			sb.WriteString("=" + m.PromotedTo.Symbol())
+		}
+	default:
+		sb.WriteString(m.Piece.Symbol())
+
+		// Find the other pieces of the same type that can move to the square
+		var sameFile, sameRank, ambiguous bool
+		others := p.Pieces(m.Piece) &^ bitboard.New(m.From)
+		for others != bitboard.Empty {
+			s := others.PopLSB()
+			if p.ValidateMove(s, m.To, piece.Piece_None) != nil {
+				continue
+			}
+			f, r := s.FileRank()
+			ambiguous = true
+			sameFile = sameFile || f == fromF
+			sameRank = sameRank || r == fromR
+		}
+
+		// Disambiguate by file, then by rank, then by square
+		switch {
+		case !ambiguous:
+		case !sameFile:
+			sb.WriteString(fromF.String())
+		case !sameRank:
+			sb.WriteString(fromR.String())
+		default:
+			sb.WriteString(m.From.String())
 		}
 		if m.IsCapture {
-			fromF, fromR := m.From.FileRank()
-			// TODO solve ambiguities
-			return SAN(fmt.Sprintf("%s%sx%s%s", fromF, fromR, m.To, promotedToStr))
+			sb.WriteString("x")
+		}
+		sb.WriteString(m.To.String())
+	}
+
+	// Add check and mate suffixes
+	p.Make(m.From, m.To, m.PromotedTo)
+	if p.InCheck() {
+		if p.HasLegalMove() {
+			sb.WriteString("+")
+		} else {
+			sb.WriteString("#")
 		}
-		return SAN(fmt.Sprintf("%s%s", m.To, promotedToStr))
 	}
+	p.Unmake()
+
+	return SAN(sb.String())
+}
+
+// ==================== Compact Move ====================
+
+// Compact packs a move in 16 bits, the from square in bits 0-5, the to square in bits 6-11
+// and the promotion piece type in bits 12-14. The side moving is left to the position.
+type Compact uint16
+
+const Compact_None Compact = 0
+
+func (m Move) Compact() Compact {
+	return Compact(m.From) | Compact(m.To)<<6 | Compact(m.PromotedTo.Abs())<<12
+}
+
+func (c Compact) From() square.Square { return square.Square(c & 0x3F) }
+func (c Compact) To() square.Square   { return square.Square(c >> 6 & 0x3F) }
+
+// PromotedTo returns the promotion piece for the side moving, or none.
+func (c Compact) PromotedTo(isWhite bool) piece.Piece {
+	pc := piece.Piece(c >> 12 & 0x7)
+	if !isWhite {
+		return -pc
+	}
+	return pc
+}
+
+// NewMoveFromCompact unpacks the move, checking it is legal in the position.
+func NewMoveFromCompact(p *position.Position, c Compact) (Move, error) {
+	if c == Compact_None {
+		return Move{}, fmt.Errorf("no move")
+	}
+	return newMove(p, c.From(), c.To(), c.PromotedTo(p.WhitesTurn))
+}
+
+// Matches reports if the move is the one packed.
+func (m Move) Matches(c Compact) bool {
+	return c != Compact_None && m.Compact() == c
 }
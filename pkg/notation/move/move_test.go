@@ -0,0 +1,231 @@
+package move_test
+
+import (
+	"testing"
+
+	"gochess/pkg/generation"
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/piece"
+	"gochess/pkg/notation/position"
+	"gochess/pkg/notation/square"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	kiwipeteFEN  position.FEN = "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1"
+	promotionFEN position.FEN = "r3k3/1P6/8/8/8/8/8/4K3 w q - 0 1"
+	enPassantFEN position.FEN = "rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3"
+)
+
+func TestNewMoveFromPCN(t *testing.T) {
+	tests := []struct {
+		fen      position.FEN
+		pcn      move.PCN
+		expected move.Move
+	}{
+		{position.StartingFEN, "e2e4", move.Move{From: square.Square_e2, To: square.Square_e4,
+			Piece: piece.Piece_WhitePawn, IsDoublePush: true}},
+		{position.StartingFEN, "g1f3", move.Move{From: square.Square_g1, To: square.Square_f3,
+			Piece: piece.Piece_WhiteKnight}},
+		{kiwipeteFEN, "e1c1", move.Move{From: square.Square_e1, To: square.Square_c1,
+			Piece: piece.Piece_WhiteKing, IsCastling: true}},
+		{kiwipeteFEN, "e5f7", move.Move{From: square.Square_e5, To: square.Square_f7,
+			Piece: piece.Piece_WhiteKnight, IsCapture: true}},
+		{promotionFEN, "b7a8q", move.Move{From: square.Square_b7, To: square.Square_a8,
+			Piece: piece.Piece_WhitePawn, PromotedTo: piece.Piece_WhiteQueen, IsCapture: true}},
+		{enPassantFEN, "e5f6", move.Move{From: square.Square_e5, To: square.Square_f6,
+			Piece: piece.Piece_WhitePawn, IsCapture: true}},
+	}
+	for _, test := range tests {
+		t.Run(string(test.pcn), func(t *testing.T) {
+			p, err := position.NewPosition(test.fen)
+			require.NoError(t, err)
+			m, err := move.NewMoveFromPCN(p, test.pcn)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, m)
+			assert.Equal(t, test.pcn, m.PCN())
+		})
+	}
+
+	// Failures
+	p, err := position.NewPosition(position.StartingFEN)
+	require.NoError(t, err)
+	_, err = move.NewMoveFromPCN(p, "e2e9")
+	assert.ErrorContains(t, err, "syntax error")
+	assert.ErrorIs(t, err, move.ErrSyntax)
+	_, err = move.NewMoveFromPCN(p, "e3e4")
+	assert.ErrorContains(t, err, "no piece on e3")
+	_, err = move.NewMoveFromPCN(p, "e7e5")
+	assert.ErrorContains(t, err, "other side's turn")
+	_, err = move.NewMoveFromPCN(p, "e2e5")
+	assert.ErrorContains(t, err, "can't move to e5")
+	_, err = move.NewMoveFromPCN(p, "e1g1")
+	assert.ErrorContains(t, err, "can't capture its own piece on g1")
+
+	p, err = position.NewPosition(promotionFEN)
+	require.NoError(t, err)
+	_, err = move.NewMoveFromPCN(p, "b7b8")
+	assert.ErrorContains(t, err, "must promote")
+	_, err = move.NewMoveFromPCN(p, "e1e2q")
+	assert.ErrorContains(t, err, "only pawns")
+
+	p, err = position.NewPosition("4k3/4r3/8/8/8/8/4N3/4K3 w - - 0 1")
+	require.NoError(t, err)
+	_, err = move.NewMoveFromPCN(p, "e2c3")
+	assert.ErrorContains(t, err, "e2c3 leaves the king in check")
+}
+
+func TestNewMoveFromLAN(t *testing.T) {
+	tests := []struct {
+		fen      position.FEN
+		lan      move.LAN
+		expected move.PCN
+	}{
+		{position.StartingFEN, "e2-e4", "e2e4"},
+		{position.StartingFEN, "e2e4", "e2e4"},
+		{position.StartingFEN, "Ng1-f3", "g1f3"},
+		{kiwipeteFEN, "O-O-O", "e1c1"},
+		{kiwipeteFEN, "Ke1-g1", "e1g1"},
+		{kiwipeteFEN, "Ne5xf7+", "e5f7"},
+		{promotionFEN, "b7xa8=N", "b7a8n"},
+		{promotionFEN, "b7-b8Q", "b7b8q"},
+		{enPassantFEN, "e5xf6", "e5f6"},
+	}
+	for _, test := range tests {
+		t.Run(string(test.lan), func(t *testing.T) {
+			p, err := position.NewPosition(test.fen)
+			require.NoError(t, err)
+			m, err := move.NewMoveFromLAN(p, test.lan)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, m.PCN())
+		})
+	}
+
+	// Failures
+	p, err := position.NewPosition(position.StartingFEN)
+	require.NoError(t, err)
+	_, err = move.NewMoveFromLAN(p, "Bg1-f3")
+	assert.ErrorContains(t, err, "piece on g1 is N not B")
+	_, err = move.NewMoveFromLAN(p, "e2xe4")
+	assert.ErrorContains(t, err, "nothing to capture")
+	_, err = move.NewMoveFromLAN(p, "O-O")
+	assert.ErrorContains(t, err, "can't capture its own piece on g1")
+
+	p, err = position.NewPosition("r3k2r/8/8/8/8/8/8/RN2K1r1 w Qkq - 0 1")
+	require.NoError(t, err)
+	_, err = move.NewMoveFromLAN(p, "O-O-O")
+	assert.ErrorContains(t, err, "pieces between king and rook prevent castling long")
+	_, err = move.NewMoveFromLAN(p, "O-O")
+	assert.ErrorContains(t, err, "no right to castle short")
+}
+
+func TestNewMoveFromSAN(t *testing.T) {
+	tests := []struct {
+		fen      position.FEN
+		san      move.SAN
+		expected move.PCN
+	}{
+		{position.StartingFEN, "e4", "e2e4"},
+		{position.StartingFEN, "Nf3", "g1f3"},
+		{kiwipeteFEN, "O-O", "e1g1"},
+		{kiwipeteFEN, "O-O-O", "e1c1"},
+		{kiwipeteFEN, "Nxf7", "e5f7"},
+		{kiwipeteFEN, "Qxf6", "f3f6"},
+		{kiwipeteFEN, "dxe6", "d5e6"},
+		{kiwipeteFEN, "Bxa6", "e2a6"},
+		{kiwipeteFEN, "Ncb5", "c3b5"},
+		{kiwipeteFEN, "Nd1", "c3d1"},
+		{kiwipeteFEN, "Nb5", "c3b5"},
+		{kiwipeteFEN, "Nd7+", "e5d7"},
+		{kiwipeteFEN, "gxh3", "g2h3"},
+		{promotionFEN, "bxa8=Q+", "b7a8q"},
+		{promotionFEN, "b8N", "b7b8n"},
+		{enPassantFEN, "exf6", "e5f6"},
+		{"4k3/8/8/8/8/8/8/R3K2R w KQ - 0 1", "Rad1", "a1d1"},
+		{"4k3/8/8/R7/8/8/8/R3K3 w Q - 0 1", "R1a3", "a1a3"},
+	}
+	for _, test := range tests {
+		t.Run(string(test.san), func(t *testing.T) {
+			p, err := position.NewPosition(test.fen)
+			require.NoError(t, err)
+			m, err := move.NewMoveFromSAN(p, test.san)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, m.PCN())
+		})
+	}
+
+	// Failures
+	p, err := position.NewPosition(kiwipeteFEN)
+	require.NoError(t, err)
+	_, err = move.NewMoveFromSAN(p, "Rb1x")
+	assert.ErrorContains(t, err, "syntax error")
+	assert.ErrorIs(t, err, move.ErrSyntax)
+	_, err = move.NewMoveFromSAN(p, "Qh8")
+	assert.ErrorContains(t, err, "illegal move Qh8: Q on f3 can't move to h8")
+	_, err = move.NewMoveFromSAN(p, "Nf3")
+	assert.ErrorContains(t, err, "no N can move to f3")
+	_, err = move.NewMoveFromSAN(p, "exd5")
+	assert.ErrorContains(t, err, "illegal move exd5")
+
+	p, err = position.NewPosition("4k3/8/8/8/8/8/8/R4RK1 w - - 0 1")
+	require.NoError(t, err)
+	_, err = move.NewMoveFromSAN(p, "Rd1")
+	assert.ErrorContains(t, err, "ambiguous move Rd1: R on a1, f1 can all move to d1")
+}
+
+func TestMoveSAN(t *testing.T) {
+	tests := []struct {
+		fen      position.FEN
+		pcn      move.PCN
+		expected move.SAN
+	}{
+		{position.StartingFEN, "e2e4", "e4"},
+		{position.StartingFEN, "g1f3", "Nf3"},
+		{kiwipeteFEN, "e1g1", "O-O"},
+		{kiwipeteFEN, "e1c1", "O-O-O"},
+		{kiwipeteFEN, "d5e6", "dxe6"},
+		{kiwipeteFEN, "e5f7", "Nxf7"},
+		{kiwipeteFEN, "g2h3", "gxh3"},
+		{enPassantFEN, "e5f6", "exf6"},
+		{promotionFEN, "b7a8q", "bxa8=Q+"},
+		{promotionFEN, "b7b8n", "b8=N"},
+		{"4k3/8/8/8/8/8/8/R4RK1 w - - 0 1", "a1d1", "Rad1"},
+		{"4k3/8/8/R7/8/8/8/R3K3 w Q - 0 1", "a1a3", "R1a3"},
+		{"4k3/8/8/8/Q6Q/8/8/4K2Q w - - 0 1", "h4e4", "Qh4e4+"},
+		{"4k3/8/8/8/Q6Q/8/8/4K2Q w - - 0 1", "a4e4", "Qae4+"},
+		{"4k3/8/8/8/8/8/2N1N3/4K3 w - - 0 1", "c2d4", "Ncd4"},
+		{"4k3/8/8/8/8/8/2N1N3/4K3 w - - 0 1", "e2c3", "Nc3"},
+		{"4k3/4r3/8/8/8/8/2N1N3/4K3 w - - 0 1", "c2d4", "Nd4"},
+		{"6k1/5ppp/8/8/8/8/8/R3K3 w - - 0 1", "a1a8", "Ra8#"},
+		{"rnbqkbnr/pppp1ppp/8/4p3/6P1/5P2/PPPPP2P/RNBQKBNR b KQkq - 0 2", "d8h4", "Qh4#"},
+	}
+	for _, test := range tests {
+		t.Run(string(test.expected), func(t *testing.T) {
+			p, err := position.NewPosition(test.fen)
+			require.NoError(t, err)
+			m, err := move.NewMoveFromPCN(p, test.pcn)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, m.SAN(p))
+			assert.Equal(t, test.fen, p.FEN())
+		})
+	}
+}
+
+func TestMoveSANRoundTrip(t *testing.T) {
+	fens := []position.FEN{position.StartingFEN, kiwipeteFEN, promotionFEN, enPassantFEN,
+		"r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1",
+		"rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8",
+	}
+	for _, fen := range fens {
+		p, err := position.NewPosition(fen)
+		require.NoError(t, err)
+		for _, m := range generation.GenerateMoves(p) {
+			san := m.SAN(p)
+			parsed, err := move.NewMoveFromSAN(p, san)
+			require.NoError(t, err, "%s %s", fen, san)
+			assert.Equal(t, *m, parsed, "%s %s", fen, san)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package pgn
+
+import (
+	"fmt"
+
+	"gochess/pkg/generation"
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/position"
+)
+
+// ==================== Result ====================
+
+type Result string
+
+const (
+	Result_WhiteWins Result = "1-0"
+	Result_BlackWins Result = "0-1"
+	Result_Draw      Result = "1/2-1/2"
+	Result_Unknown   Result = "*"
+)
+
+func isResult(s string) bool {
+	switch Result(s) {
+	case Result_WhiteWins, Result_BlackWins, Result_Draw, Result_Unknown:
+		return true
+	default:
+		return false
+	}
+}
+
+// ==================== Node ====================
+
+// Node is a ply of the move tree. The first child continues the line, the others are variations.
+type Node struct {
+	Move       move.Move
+	Position   *position.Position // Position after the move
+	PreComment string             // Comment before the first move of a variation
+	Comment    string
+	NAGs       []int
+	Parent     *Node
+	Children   []*Node
+}
+
+// IsRoot reports if the node holds the starting position rather than a move.
+func (n *Node) IsRoot() bool {
+	return n.Parent == nil
+}
+
+// SAN returns the move of the node in Standard Algebraic Notation.
+func (n *Node) SAN() move.SAN {
+	if n.IsRoot() {
+		return ""
+	}
+	return n.Move.SAN(n.Parent.Position)
+}
+
+// AddMove plays the move after the node, as the main line if it is the first child
+// or as a variation otherwise. The move is expected to be legal in the position.
+func (n *Node) AddMove(m move.Move) *Node {
+	child := &Node{
+		Move:     m,
+		Position: generation.MakeMove(n.Position, m),
+		Parent:   n,
+	}
+	n.Children = append(n.Children, child)
+	return child
+}
+
+// ==================== Game ====================
+
+type Game struct {
+	Tags   map[string]string
+	Root   *Node
+	Result Result
+}
+
+// NewGame returns a game without moves, starting from the position.
+func NewGame(fen position.FEN) (*Game, error) {
+	p, err := position.NewPosition(fen)
+	if err != nil {
+		return nil, fmt.Errorf("invalid starting position: %w", err)
+	}
+	g := &Game{
+		Tags:   map[string]string{},
+		Root:   &Node{Position: p},
+		Result: Result_Unknown,
+	}
+	if fen != position.StartingFEN {
+		g.Tags["SetUp"] = "1"
+		g.Tags["FEN"] = string(fen)
+	}
+	return g, nil
+}
+
+// MainLine returns the nodes of the main line, without the root.
+func (g *Game) MainLine() []*Node {
+	var nodes []*Node
+	for n := g.Root; len(n.Children) > 0; {
+		n = n.Children[0]
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// Last returns the last node of the main line.
+func (g *Game) Last() *Node {
+	n := g.Root
+	for len(n.Children) > 0 {
+		n = n.Children[0]
+	}
+	return n
+}
+
+// Positions returns the starting position followed by the position after each ply of the main line.
+func (g *Game) Positions() []*position.Position {
+	positions := []*position.Position{g.Root.Position}
+	for _, n := range g.MainLine() {
+		positions = append(positions, n.Position)
+	}
+	return positions
+}
@@ -0,0 +1,341 @@
+package pgn
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/position"
+)
+
+// ==================== Scanner ====================
+
+type tokenKind int
+
+const (
+	token_EOF tokenKind = iota
+	token_Tag
+	token_Comment
+	token_NAG
+	token_MoveNumber
+	token_Symbol
+	token_Result
+	token_OpenVariation
+	token_CloseVariation
+)
+
+type token struct {
+	kind  tokenKind
+	text  string // Tag name, comment, NAG, move or result
+	value string // Tag value
+	line  int
+}
+
+// scanner splits PGN text into tokens, skipping whitespace and escaped lines.
+type scanner struct {
+	r         *bufio.Reader
+	line      int
+	lineStart bool
+}
+
+func isSymbolRune(c rune) bool {
+	return c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c) || strings.ContainsRune("_+#=:-/!?", c))
+}
+
+func (s *scanner) read() (rune, bool) {
+	c, _, err := s.r.ReadRune()
+	if err != nil {
+		return 0, false
+	}
+	if c == '\n' {
+		s.line++
+		s.lineStart = true
+	} else {
+		s.lineStart = false
+	}
+	return c, true
+}
+
+func (s *scanner) unread(c rune) {
+	_ = s.r.UnreadRune()
+	if c == '\n' {
+		s.line--
+	}
+}
+
+// readWhile reads the runes accepted by the function.
+func (s *scanner) readWhile(accept func(rune) bool) string {
+	var sb strings.Builder
+	for {
+		c, ok := s.read()
+		if !ok {
+			return sb.String()
+		}
+		if !accept(c) {
+			s.unread(c)
+			return sb.String()
+		}
+		sb.WriteRune(c)
+	}
+}
+
+func (s *scanner) skipSpaces() {
+	s.readWhile(unicode.IsSpace)
+}
+
+func (s *scanner) next() (token, error) {
+	for {
+		lineStart := s.lineStart
+		c, ok := s.read()
+		if !ok {
+			return token{kind: token_EOF, line: s.line}, nil
+		}
+		line := s.line
+
+		switch {
+		case c == '%' && lineStart:
+			// Escaped line
+			s.readWhile(func(c rune) bool { return c != '\n' })
+		case unicode.IsSpace(c), c == '.':
+		case c == '[':
+			return s.tag(line)
+		case c == '{':
+			text := s.readWhile(func(c rune) bool { return c != '}' })
+			if c, ok := s.read(); !ok || c != '}' {
+				return token{}, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			return token{kind: token_Comment, text: strings.Join(strings.Fields(text), " "), line: line}, nil
+		case c == ';':
+			text := s.readWhile(func(c rune) bool { return c != '\n' })
+			return token{kind: token_Comment, text: strings.TrimSpace(text), line: line}, nil
+		case c == '$':
+			text := s.readWhile(unicode.IsDigit)
+			if text == "" {
+				return token{}, fmt.Errorf("line %d: missing NAG number", line)
+			}
+			return token{kind: token_NAG, text: text, line: line}, nil
+		case c == '(':
+			return token{kind: token_OpenVariation, line: line}, nil
+		case c == ')':
+			return token{kind: token_CloseVariation, line: line}, nil
+		case c == '*':
+			return token{kind: token_Result, text: string(Result_Unknown), line: line}, nil
+		case isSymbolRune(c):
+			s.unread(c)
+			text := s.readWhile(isSymbolRune)
+			if isResult(text) {
+				return token{kind: token_Result, text: text, line: line}, nil
+			}
+			if strings.Trim(text, "0123456789") == "" {
+				s.readWhile(func(c rune) bool { return c == '.' })
+				return token{kind: token_MoveNumber, text: text, line: line}, nil
+			}
+			return token{kind: token_Symbol, text: text, line: line}, nil
+		default:
+			return token{}, fmt.Errorf("line %d: unexpected character %q", line, c)
+		}
+	}
+}
+
+// tag reads a tag pair after its opening bracket.
+func (s *scanner) tag(line int) (token, error) {
+	s.skipSpaces()
+	name := s.readWhile(isSymbolRune)
+	if name == "" {
+		return token{}, fmt.Errorf("line %d: missing tag name", line)
+	}
+	s.skipSpaces()
+	if c, ok := s.read(); !ok || c != '"' {
+		return token{}, fmt.Errorf("line %d: missing value of tag %s", line, name)
+	}
+
+	// Read the value, unescaping quotes and backslashes
+	var sb strings.Builder
+	for {
+		c, ok := s.read()
+		if !ok || c == '\n' {
+			return token{}, fmt.Errorf("line %d: unterminated value of tag %s", line, name)
+		}
+		if c == '"' {
+			break
+		}
+		if c == '\\' {
+			if c, ok = s.read(); !ok {
+				return token{}, fmt.Errorf("line %d: unterminated value of tag %s", line, name)
+			}
+		}
+		sb.WriteRune(c)
+	}
+
+	s.skipSpaces()
+	if c, ok := s.read(); !ok || c != ']' {
+		return token{}, fmt.Errorf("line %d: missing ] after tag %s", line, name)
+	}
+	return token{kind: token_Tag, text: name, value: sb.String(), line: line}, nil
+}
+
+// ==================== Reader ====================
+
+// suffixNAGs are the NAGs of the move suffix annotations.
+var suffixNAGs = map[string]int{"!": 1, "?": 2, "!!": 3, "??": 4, "!?": 5, "?!": 6}
+
+// Reader reads games from PGN text, replaying their moves.
+type Reader struct {
+	s      scanner
+	peeked *token
+	games  int
+	ended  bool // If the last game read reached its end
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{s: scanner{r: bufio.NewReader(r), line: 1, lineStart: true}}
+}
+
+func (r *Reader) next() (token, error) {
+	if r.peeked != nil {
+		t := *r.peeked
+		r.peeked = nil
+		return t, nil
+	}
+	return r.s.next()
+}
+
+// Read returns the next game, or io.EOF when there are no more games.
+// After an error the rest of the game is skipped, so reading can go on with the next one.
+func (r *Reader) Read() (*Game, error) {
+	r.ended = false
+	g, err := r.read()
+	if err != nil && !errors.Is(err, io.EOF) {
+		if !r.ended {
+			r.skipGame()
+		}
+		return nil, fmt.Errorf("game %d: %w", r.games, err)
+	}
+	return g, err
+}
+
+// ReadAll returns all the remaining games, stopping at the first error.
+func (r *Reader) ReadAll() ([]*Game, error) {
+	var games []*Game
+	for {
+		g, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return games, nil
+		}
+		if err != nil {
+			return games, err
+		}
+		games = append(games, g)
+	}
+}
+
+func (r *Reader) read() (*Game, error) {
+	// Read tag pairs
+	tags := map[string]string{}
+	t, err := r.next()
+	for ; err == nil && t.kind == token_Tag; t, err = r.next() {
+		tags[t.text] = t.value
+	}
+	if err != nil {
+		r.games++
+		return nil, err
+	}
+	if t.kind == token_EOF && len(tags) == 0 {
+		return nil, io.EOF
+	}
+	r.games++
+
+	// Find the starting position
+	fen := position.StartingFEN
+	if tags["FEN"] != "" {
+		fen = position.FEN(tags["FEN"])
+	}
+	g, err := NewGame(fen)
+	if err != nil {
+		return nil, err
+	}
+	g.Tags = tags
+	if result, ok := tags["Result"]; ok && isResult(result) {
+		g.Result = Result(result)
+	}
+
+	// Read the movetext
+	cur := g.Root
+	var variations []*Node
+	var variationStart bool
+	var preComment string
+	for ; err == nil; t, err = r.next() {
+		switch t.kind {
+		case token_MoveNumber:
+		case token_Symbol:
+			san := strings.TrimRight(t.text, "!?")
+			m, err := move.NewMoveFromSAN(cur.Position, move.SAN(san))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", t.line, err)
+			}
+			cur = cur.AddMove(m)
+			cur.PreComment, preComment = preComment, ""
+			if nag, ok := suffixNAGs[t.text[len(san):]]; ok {
+				cur.NAGs = append(cur.NAGs, nag)
+			}
+			variationStart = false
+		case token_Comment:
+			if variationStart {
+				preComment = strings.TrimSpace(preComment + " " + t.text)
+			} else {
+				cur.Comment = strings.TrimSpace(cur.Comment + " " + t.text)
+			}
+		case token_NAG:
+			nag, _ := strconv.Atoi(t.text)
+			cur.NAGs = append(cur.NAGs, nag)
+		case token_OpenVariation:
+			if cur.IsRoot() {
+				return nil, fmt.Errorf("line %d: variation before the first move", t.line)
+			}
+			variations = append(variations, cur)
+			cur = cur.Parent
+			variationStart = true
+		case token_CloseVariation:
+			if len(variations) == 0 {
+				return nil, fmt.Errorf("line %d: unexpected end of variation", t.line)
+			}
+			cur = variations[len(variations)-1]
+			variations = variations[:len(variations)-1]
+		case token_Result, token_Tag, token_EOF:
+			r.ended = true
+			if len(variations) > 0 {
+				return nil, fmt.Errorf("line %d: unterminated variation", t.line)
+			}
+			if t.kind == token_Result {
+				g.Result = Result(t.text)
+			} else if t.kind == token_Tag {
+				// The game ended without a result, the tag belongs to the next one
+				r.peeked = &t
+			}
+			return g, nil
+		}
+	}
+	return nil, err
+}
+
+// skipGame skips tokens up to the end of the current game.
+func (r *Reader) skipGame() {
+	for {
+		t, err := r.next()
+		if err != nil {
+			continue
+		}
+		switch t.kind {
+		case token_Result, token_EOF:
+			return
+		case token_Tag:
+			r.peeked = &t
+			return
+		}
+	}
+}
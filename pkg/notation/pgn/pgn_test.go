@@ -0,0 +1,176 @@
+package pgn_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/pgn"
+	"gochess/pkg/notation/position"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPGN = `[Event "F/S Return Match"]
+[Site "Belgrade, Serbia JUG"]
+[Date "1992.11.04"]
+[Round "29"]
+[White "Fischer, Robert J."]
+[Black "Spassky, Boris V."]
+[Result "1/2-1/2"]
+
+1. e4 e5 2. Nf3 Nc6 3. Bb5 a6 {This opening is called the Ruy Lopez.}
+4. Ba4 Nf6 5. O-O Be7 6. Re1 b5 7. Bb3 d6 8. c3 O-O 9. h3 Nb8 10. d4 Nbd7
+11. c4 c6 12. cxb5 axb5 13. Nc3 Bb7 14. Bg5 b4 15. Nb1 h6 16. Bh4 c5 17. dxe5
+Nxe4 18. Bxe7 Qxe7 19. exd6 Qf6 20. Nbd2 Nxd6 21. Nc4 Nxc4 22. Bxc4 Nb6
+23. Ne5 Rae8 24. Bxf7+ Rxf7 25. Nxf7 Rxe1+ 26. Qxe1 Kxf7 27. Qe3 Qg5 28. Qxg5
+hxg5 29. b3 Ke6 30. a3 Kd6 31. axb4 cxb4 32. Ra5 Nd5 33. f3 Bc8 34. Kf2 Bf5
+35. Ra7 g6 36. Ra6+ Kc5 37. Ke1 Nf4 38. g3 Nxh3 39. Kd2 Kb5 40. Rd6 Kc5 41. Ra6
+Nf2 42. g4 Bd3 43. Re6 1/2-1/2
+
+% Escaped line
+[Event "Annotated"]
+[SetUp "1"]
+[FEN "4k3/8/8/8/8/8/4P3/4K3 b - - 0 10"]
+
+{Black to move} 10... Kd7 $1 (10... Kf7? {too slow} 11. e4 (11. Kf2 Ke6) Ke6)
+11. e4! ; line comment
+Ke6 *
+`
+
+func TestReader(t *testing.T) {
+	games, err := pgn.NewReader(strings.NewReader(testPGN)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, games, 2)
+
+	// Game 1
+	g := games[0]
+	assert.Equal(t, "Fischer, Robert J.", g.Tags["White"])
+	assert.Equal(t, pgn.Result_Draw, g.Result)
+	mainLine := g.MainLine()
+	assert.Len(t, mainLine, 85)
+	assert.Equal(t, "This opening is called the Ruy Lopez.", mainLine[5].Comment)
+	assert.Equal(t, move.SAN("O-O"), mainLine[8].SAN())
+	assert.Equal(t, move.SAN("Bxf7+"), mainLine[46].SAN())
+	positions := g.Positions()
+	assert.Len(t, positions, 86)
+	assert.Equal(t, position.StartingFEN, positions[0].FEN())
+	assert.Equal(t, position.FEN("8/8/4R1p1/2k3p1/1p4P1/1P1b1P2/3K1n2/8 b - - 2 43"), positions[85].FEN())
+
+	// Game 2
+	g = games[1]
+	assert.Equal(t, pgn.Result_Unknown, g.Result)
+	assert.Equal(t, "Black to move", g.Root.Comment)
+	require.Len(t, g.Root.Children, 2)
+	main, variation := g.Root.Children[0], g.Root.Children[1]
+	assert.Equal(t, move.SAN("Kd7"), main.SAN())
+	assert.Equal(t, []int{1}, main.NAGs)
+	assert.Equal(t, move.SAN("Kf7"), variation.SAN())
+	assert.Equal(t, []int{2}, variation.NAGs)
+	assert.Equal(t, "too slow", variation.Comment)
+	require.Len(t, variation.Children, 2)
+	assert.Equal(t, move.SAN("Kf2"), variation.Children[1].SAN())
+	assert.Equal(t, move.SAN("Ke6"), variation.Children[0].Children[0].SAN())
+	assert.Equal(t, "line comment", main.Children[0].Comment)
+	assert.Equal(t, position.FEN("8/8/4k3/8/4P3/8/8/4K3 w - - 1 12"), g.Last().Position.FEN())
+}
+
+func TestReaderErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		pgn      string
+		expected string
+	}{
+		{"Illegal Move", "1. e4 e5 2. Ke3 *", "game 1: line 1: illegal move Ke3"},
+		{"Ambiguous Move", "[Event \"?\"]\n[FEN \"4k3/8/8/8/8/8/8/R4RK1 w - - 0 1\"]\n\n1. Rd1 *", "game 1: line 4: ambiguous move Rd1"},
+		{"Unterminated Variation", "1. e4 (1. d4 *", "game 1: line 1: unterminated variation"},
+		{"Unterminated Comment", "1. e4 {open", "game 1: line 1: unterminated comment"},
+		{"Bad Tag", "[Event Casual]\n1. e4 *", "game 1: line 1: missing value of tag Event"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := pgn.NewReader(strings.NewReader(test.pgn)).Read()
+			assert.ErrorContains(t, err, test.expected)
+		})
+	}
+
+	// Reading goes on with the next game
+	r := pgn.NewReader(strings.NewReader("1. e4 e5 2. Ke3 Nc6 1-0\n\n1. d4 d5 0-1\n"))
+	_, err := r.Read()
+	assert.Error(t, err)
+	g, err := r.Read()
+	require.NoError(t, err)
+	assert.Equal(t, pgn.Result_BlackWins, g.Result)
+	assert.Len(t, g.MainLine(), 2)
+	_, err = r.Read()
+	assert.True(t, errors.Is(err, io.EOF))
+}
+
+func TestWriter(t *testing.T) {
+	g, err := pgn.NewGame("4k3/8/8/8/8/8/4P3/4K3 b - - 0 10")
+	require.NoError(t, err)
+	g.Tags["White"] = `Bobby "The Kid"`
+	g.Tags["Annotator"] = "gochess"
+	g.Result = pgn.Result_WhiteWins
+
+	// Build the move tree
+	play := func(n *pgn.Node, san move.SAN) *pgn.Node {
+		m, err := move.NewMoveFromSAN(n.Position, san)
+		require.NoError(t, err)
+		return n.AddMove(m)
+	}
+	n := play(g.Root, "Kd7")
+	n.NAGs = []int{1}
+	v := play(g.Root, "Kf7")
+	v.Comment = "too slow"
+	play(play(v, "e4"), "Ke6")
+	n = play(n, "e4")
+	n.Comment = "the pawn runs"
+	play(n, "Ke6")
+
+	expected := `[Event "?"]
+[Site "?"]
+[Date "????.??.??"]
+[Round "?"]
+[White "Bobby \"The Kid\""]
+[Black "?"]
+[Result "1-0"]
+[Annotator "gochess"]
+[FEN "4k3/8/8/8/8/8/4P3/4K3 b - - 0 10"]
+[SetUp "1"]
+
+10... Kd7 $1 (10... Kf7 {too slow} 11. e4 Ke6) 11. e4 {the pawn runs} 11... Ke6
+1-0
+
+`
+	var buf bytes.Buffer
+	require.NoError(t, pgn.NewWriter(&buf).Write(g))
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	games, err := pgn.NewReader(strings.NewReader(testPGN)).ReadAll()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	w := pgn.NewWriter(&buf)
+	for _, g := range games {
+		require.NoError(t, w.Write(g))
+	}
+	for _, line := range strings.Split(buf.String(), "\n") {
+		assert.LessOrEqual(t, len(line), 80)
+	}
+
+	// Reading the written games gives them back
+	written, err := pgn.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, written, len(games))
+	for i := range games {
+		assert.Equal(t, games[i].String(), written[i].String())
+		assert.Equal(t, games[i].Last().Position.FEN(), written[i].Last().Position.FEN())
+	}
+}
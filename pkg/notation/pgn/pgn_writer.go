@@ -0,0 +1,166 @@
+package pgn
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+type tagPair struct {
+	name  string
+	value string
+}
+
+// sevenTagRoster are the tags written first in export format, with their default values.
+var sevenTagRoster = []tagPair{
+	{"Event", "?"},
+	{"Site", "?"},
+	{"Date", "????.??.??"},
+	{"Round", "?"},
+	{"White", "?"},
+	{"Black", "?"},
+	{"Result", string(Result_Unknown)},
+}
+
+// maxLineLength is the length of the movetext lines in export format.
+const maxLineLength = 80
+
+// Writer writes games as PGN in export format.
+type Writer struct {
+	w io.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write writes the game followed by an empty line.
+func (w *Writer) Write(g *Game) error {
+	_, err := io.WriteString(w.w, g.String()+"\n")
+	return err
+}
+
+// String returns the game in export format.
+func (g *Game) String() string {
+	var sb strings.Builder
+	result := g.Result
+	if result == "" {
+		result = Result_Unknown
+	}
+
+	// Write the seven tag roster then the other tags in ASCII order
+	for _, tag := range sevenTagRoster {
+		value := tag.value
+		if v, ok := g.Tags[tag.name]; ok && v != "" {
+			value = v
+		}
+		if tag.name == "Result" {
+			value = string(result)
+		}
+		writeTag(&sb, tag.name, value)
+	}
+	var names []string
+	for name := range g.Tags {
+		if !slices.ContainsFunc(sevenTagRoster, func(t tagPair) bool { return t.name == name }) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		writeTag(&sb, name, g.Tags[name])
+	}
+	sb.WriteString("\n")
+
+	// Write the movetext
+	var tokens []string
+	if g.Root.Comment != "" {
+		tokens = append(tokens, commentToken(g.Root.Comment))
+	}
+	tokens = appendLine(tokens, g.Root, true)
+	tokens = append(tokens, string(result))
+	sb.WriteString(wrap(tokens))
+	return sb.String()
+}
+
+func writeTag(sb *strings.Builder, name, value string) {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	fmt.Fprintf(sb, "[%s \"%s\"]\n", name, value)
+}
+
+func commentToken(comment string) string {
+	return "{" + strings.ReplaceAll(comment, "}", ")") + "}"
+}
+
+// appendLine appends the tokens of the moves after the node, with their variations.
+func appendLine(tokens []string, n *Node, needNumber bool) []string {
+	for len(n.Children) > 0 {
+		main := n.Children[0]
+		tokens = appendMove(tokens, main, needNumber)
+		needNumber = main.Comment != ""
+
+		// Variations are alternatives to the main move
+		for _, v := range n.Children[1:] {
+			start := len(tokens)
+			tokens = appendMove(tokens, v, true)
+			tokens = appendLine(tokens, v, v.Comment != "")
+			tokens[start] = "(" + tokens[start]
+			tokens[len(tokens)-1] += ")"
+			needNumber = true
+		}
+		n = main
+	}
+	return tokens
+}
+
+// appendMove appends the tokens of the move of the node, with its number, NAGs and comments.
+func appendMove(tokens []string, n *Node, needNumber bool) []string {
+	p := n.Parent.Position
+	if n.PreComment != "" {
+		tokens = append(tokens, commentToken(n.PreComment))
+	}
+	san := string(n.SAN())
+	switch {
+	case p.WhitesTurn:
+		san = fmt.Sprintf("%d. %s", p.FullmoveCount, san)
+	case needNumber || n.PreComment != "":
+		san = fmt.Sprintf("%d... %s", p.FullmoveCount, san)
+	}
+	tokens = append(tokens, san)
+	for _, nag := range n.NAGs {
+		tokens = append(tokens, fmt.Sprintf("$%d", nag))
+	}
+	if n.Comment != "" {
+		tokens = append(tokens, commentToken(n.Comment))
+	}
+	return tokens
+}
+
+// wrap joins the tokens with spaces into lines no longer than maxLineLength when possible.
+// Only comments are split across lines, a move stays with its number.
+func wrap(tokens []string) string {
+	var sb strings.Builder
+	lineLength := 0
+	for _, token := range tokens {
+		words := []string{token}
+		if strings.Contains(token, "{") {
+			words = strings.Fields(token)
+		}
+		for _, word := range words {
+			switch {
+			case lineLength == 0:
+			case lineLength+1+len(word) > maxLineLength:
+				sb.WriteString("\n")
+				lineLength = 0
+			default:
+				sb.WriteString(" ")
+				lineLength++
+			}
+			sb.WriteString(word)
+			lineLength += len(word)
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
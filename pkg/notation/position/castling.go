@@ -1,5 +1,9 @@
 package position
 
+import (
+	"gochess/pkg/notation/square"
+)
+
 type Castling uint8
 
 const (
@@ -17,3 +21,58 @@ const (
 
 	Castling_Any Castling = Castling_White | Castling_Black
 )
+
+func NewCastling(isWhite, isShort bool) Castling {
+	c := Castling_White
+	if !isWhite {
+		c = Castling_Black
+	}
+	if isShort {
+		return c & Castling_KingSide
+	}
+	return c & Castling_QueeenSide
+}
+
+func (c Castling) Has(o Castling) bool { return c&o != 0 }
+
+// CastlingLostFrom returns the castling rights lost when a piece moves from or to the square.
+func CastlingLostFrom(s square.Square) Castling {
+	switch s {
+	case square.Square_e1:
+		return Castling_White
+	case square.Square_h1:
+		return Castling_WhiteOO
+	case square.Square_a1:
+		return Castling_WhiteOOO
+	case square.Square_e8:
+		return Castling_Black
+	case square.Square_h8:
+		return Castling_BlackOO
+	case square.Square_a8:
+		return Castling_BlackOOO
+	default:
+		return Castling_None
+	}
+}
+
+// Castling ability: If neither side can castle, the symbol '-' is used, otherwise each of four individual
+// castling rights for king and queen castling for both sides are indicated by a sequence of one to four letters.
+func (c Castling) String() string {
+	castlingStr := ""
+	if c.Has(Castling_WhiteOO) {
+		castlingStr += "K"
+	}
+	if c.Has(Castling_WhiteOOO) {
+		castlingStr += "Q"
+	}
+	if c.Has(Castling_BlackOO) {
+		castlingStr += "k"
+	}
+	if c.Has(Castling_BlackOOO) {
+		castlingStr += "q"
+	}
+	if castlingStr == "" {
+		castlingStr = "-"
+	}
+	return castlingStr
+}
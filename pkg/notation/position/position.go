@@ -1,6 +1,9 @@
 package position
 
 import (
+	"slices"
+
+	"gochess/pkg/bitboard"
 	"gochess/pkg/notation/piece"
 	"gochess/pkg/notation/square"
 )
@@ -11,21 +14,31 @@ func NewPosition(fenStr FEN) (*Position, error) {
 	if err != nil {
 		return nil, err
 	}
+	p.Hash = p.computeHash()
+	p.PawnHash = p.computePawnHash()
 	return p, nil
 }
 
 type Position struct {
-	PieceList  []piece.Piece
-	WhitesTurn bool
-	Castling   struct {
-		whiteShort bool
-		whiteLong  bool
-		blackShort bool
-		blackLong  bool
-	}
+	PieceList       []piece.Piece
+	WhitesTurn      bool
+	Castling        Castling
 	EnPassantSquare square.Square
 	HalfmoveCount   int
 	FullmoveCount   int
+
+	// Bitboards mirror PieceList, indexed by piece value offset by piece.Piece_King
+	// and by color, white first. Only change pieces through putPiece and removePiece.
+	PieceBoards [13]bitboard.Bitboard
+	ColorBoards [2]bitboard.Bitboard
+
+	// Hash is the Zobrist hash of the position, updated incrementally by Make and Unmake
+	Hash uint64
+
+	// PawnHash is the Zobrist hash of the pawns alone
+	PawnHash uint64
+
+	undoStack []undo
 }
 
 func (p Position) String() string {
@@ -34,46 +47,107 @@ func (p Position) String() string {
 
 // ==================== Piece Functions ====================
 
-func (p Position) PieceAt(s square.Square) piece.Piece {
+func (p *Position) PieceAt(s square.Square) piece.Piece {
 	// TODO check if square is valid
 	return p.PieceList[int(s)]
 }
 
+func (p *Position) putPiece(s square.Square, pc piece.Piece) {
+	if pc == piece.Piece_None {
+		return
+	}
+	p.PieceList[int(s)] = pc
+	p.Hash ^= zobristPiece(pc, s)
+	if pc.IsPawn() {
+		p.PawnHash ^= zobristPiece(pc, s)
+	}
+	p.PieceBoards[pc+piece.Piece_King] = p.PieceBoards[pc+piece.Piece_King].Set(s)
+	p.ColorBoards[colorIndex(pc.IsWhite())] = p.ColorBoards[colorIndex(pc.IsWhite())].Set(s)
+}
+
+func (p *Position) removePiece(s square.Square) piece.Piece {
+	pc := p.PieceList[int(s)]
+	if pc == piece.Piece_None {
+		return pc
+	}
+	p.PieceList[int(s)] = piece.Piece_None
+	p.Hash ^= zobristPiece(pc, s)
+	if pc.IsPawn() {
+		p.PawnHash ^= zobristPiece(pc, s)
+	}
+	p.PieceBoards[pc+piece.Piece_King] = p.PieceBoards[pc+piece.Piece_King].Clear(s)
+	p.ColorBoards[colorIndex(pc.IsWhite())] = p.ColorBoards[colorIndex(pc.IsWhite())].Clear(s)
+	return pc
+}
+
+// ==================== Bitboard Functions ====================
+
+func colorIndex(isWhite bool) int {
+	if isWhite {
+		return 0
+	}
+	return 1
+}
+
+// Pieces returns the squares holding the piece.
+func (p *Position) Pieces(pc piece.Piece) bitboard.Bitboard {
+	return p.PieceBoards[pc+piece.Piece_King]
+}
+
+// PiecesOfType returns the squares holding the piece type of either color.
+func (p *Position) PiecesOfType(pc piece.Piece) bitboard.Bitboard {
+	return p.PieceBoards[pc.Abs()+piece.Piece_King] | p.PieceBoards[piece.Piece_King-pc.Abs()]
+}
+
+// Colored returns the squares holding pieces of the color.
+func (p *Position) Colored(isWhite bool) bitboard.Bitboard {
+	return p.ColorBoards[colorIndex(isWhite)]
+}
+
+// Occupied returns the squares holding any piece.
+func (p *Position) Occupied() bitboard.Bitboard {
+	return p.ColorBoards[0] | p.ColorBoards[1]
+}
+
 // ==================== Castling Functions ====================
 
 func (p Position) CanWhiteCastle() bool {
-	return p.Castling.whiteLong || p.Castling.whiteShort
+	return p.Castling.Has(Castling_White)
 }
 
 func (p Position) CanBlackCastle() bool {
-	return p.Castling.blackLong || p.Castling.blackShort
+	return p.Castling.Has(Castling_Black)
 }
 
 func (p Position) CanCastle(isWhite, isShort bool) bool {
-	if isWhite && isShort {
-		return p.Castling.whiteShort
-	} else if isWhite && !isShort {
-		return p.Castling.whiteLong
-	} else if !isWhite && isShort {
-		return p.Castling.blackShort
-	} else if !isWhite && !isShort {
-		return p.Castling.blackLong
-	}
-	return false
+	return p.Castling.Has(NewCastling(isWhite, isShort))
 }
 
 // ==================== Ascii ====================
 
 func (p Position) AsciiString() string {
+	return p.AsciiStringFrom(true)
+}
+
+// AsciiStringFrom returns the board as seen by the color, its pieces at the bottom.
+func (p Position) AsciiStringFrom(isWhite bool) string {
 	var asciiString string
 
+	// Order the ranks and files from the top left corner
+	ranks := []square.Rank{square.Rank8, square.Rank7, square.Rank6, square.Rank5, square.Rank4, square.Rank3, square.Rank2, square.Rank1}
+	files := []square.File{square.FileA, square.FileB, square.FileC, square.FileD, square.FileE, square.FileF, square.FileG, square.FileH}
+	if !isWhite {
+		slices.Reverse(ranks)
+		slices.Reverse(files)
+	}
+
 	// Top of board
 	asciiString += "\n +---+---+---+---+---+---+---+---+\n"
 
 	// Each Rank
-	for r := square.Rank8; r >= square.Rank1; r-- {
+	for _, r := range ranks {
 		// Each File
-		for f := square.FileA; f <= square.FileH; f++ {
+		for _, f := range files {
 			// Each Piece
 			asciiString += " | " + p.PieceAt(square.NewSquare(f, r)).String()
 		}
@@ -82,7 +156,11 @@ func (p Position) AsciiString() string {
 	}
 
 	// Bottom of board
-	asciiString += "   a   b   c   d   e   f   g   h \n\n"
+	asciiString += " "
+	for _, f := range files {
+		asciiString += "  " + f.String() + " "
+	}
+	asciiString += "\n\n"
 
 	// Extras
 	asciiString += "FEN: " + string(p.FEN()) + ""
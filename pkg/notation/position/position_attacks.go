@@ -0,0 +1,39 @@
+package position
+
+import (
+	"gochess/pkg/bitboard"
+	"gochess/pkg/notation/piece"
+	"gochess/pkg/notation/square"
+)
+
+// AttackersTo returns the pieces of both colors attacking the square, with sliding
+// attacks blocked by the given occupied squares.
+func (p *Position) AttackersTo(s square.Square, occupied bitboard.Bitboard) bitboard.Bitboard {
+	rooks := p.PiecesOfType(piece.Piece_Rook) | p.PiecesOfType(piece.Piece_Queen)
+	bishops := p.PiecesOfType(piece.Piece_Bishop) | p.PiecesOfType(piece.Piece_Queen)
+	return (bitboard.PawnAttack(false, s) & p.Pieces(piece.Piece_WhitePawn)) |
+		(bitboard.PawnAttack(true, s) & p.Pieces(piece.Piece_BlackPawn)) |
+		(bitboard.KnightAttacks[s] & p.PiecesOfType(piece.Piece_Knight)) |
+		(bitboard.KingAttacks[s] & p.PiecesOfType(piece.Piece_King)) |
+		(bitboard.RookAttacks(s, occupied) & rooks) |
+		(bitboard.BishopAttacks(s, occupied) & bishops)
+}
+
+// IsSquareAttacked reports if any piece of the given color attacks the square.
+func (p *Position) IsSquareAttacked(s square.Square, byWhite bool) bool {
+	return p.AttackersTo(s, p.Occupied())&p.Colored(byWhite) != 0
+}
+
+// KingSquare returns the square of the king of the given color.
+func (p *Position) KingSquare(isWhite bool) square.Square {
+	if isWhite {
+		return p.Pieces(piece.Piece_WhiteKing).LSB()
+	}
+	return p.Pieces(piece.Piece_BlackKing).LSB()
+}
+
+// InCheck reports if the side to move is in check.
+func (p *Position) InCheck() bool {
+	kingSquare := p.KingSquare(p.WhitesTurn)
+	return kingSquare != square.Square_Invalid && p.IsSquareAttacked(kingSquare, !p.WhitesTurn)
+}
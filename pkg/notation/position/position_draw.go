@@ -0,0 +1,33 @@
+package position
+
+import (
+	"gochess/pkg/bitboard"
+	"gochess/pkg/notation/piece"
+)
+
+// Repetitions returns how many times the position occurred, counting the current one.
+// Only positions reached with Make since the last capture or pawn move are compared.
+func (p *Position) Repetitions() int {
+	count := 1
+	oldest := len(p.undoStack) - p.HalfmoveCount
+	for i := len(p.undoStack) - 2; i >= 0 && i >= oldest; i -= 2 {
+		if p.undoStack[i].hash == p.Hash {
+			count++
+		}
+	}
+	return count
+}
+
+// HasInsufficientMaterial reports if neither side can checkmate whatever the moves,
+// with a lone minor piece or only bishops on squares of the same color left.
+func (p *Position) HasInsufficientMaterial() bool {
+	if p.PiecesOfType(piece.Piece_Pawn)|p.PiecesOfType(piece.Piece_Rook)|p.PiecesOfType(piece.Piece_Queen) != bitboard.Empty {
+		return false
+	}
+	knights := p.PiecesOfType(piece.Piece_Knight)
+	bishops := p.PiecesOfType(piece.Piece_Bishop)
+	if (knights | bishops).Count() <= 1 {
+		return true
+	}
+	return knights == bitboard.Empty && (bishops&bitboard.LightSquares == bitboard.Empty || bishops&bitboard.DarkSquares == bitboard.Empty)
+}
@@ -121,7 +121,7 @@ func (p *Position) parseFEN(fenStr FEN) error {
 				if err != nil {
 					return err
 				}
-				p.PieceList[index] = v
+				p.putPiece(square.Square(index), v)
 				index++
 			} else {
 				return fmt.Errorf("invalid piece syntax")
@@ -133,10 +133,15 @@ func (p *Position) parseFEN(fenStr FEN) error {
 	p.WhitesTurn = (submatches[2] == "w")
 
 	// Parse Castling
-	p.Castling.whiteShort = strings.Contains(submatches[3], "K")
-	p.Castling.whiteLong = strings.Contains(submatches[3], "Q")
-	p.Castling.blackShort = strings.Contains(submatches[3], "k")
-	p.Castling.blackLong = strings.Contains(submatches[3], "q")
+	p.Castling = Castling_None
+	for _, c := range []struct {
+		char     string
+		castling Castling
+	}{{"K", Castling_WhiteOO}, {"Q", Castling_WhiteOOO}, {"k", Castling_BlackOO}, {"q", Castling_BlackOOO}} {
+		if strings.Contains(submatches[3], c.char) {
+			p.Castling |= c.castling
+		}
+	}
 
 	// Parse En Passant Square
 	var err error
@@ -200,22 +205,7 @@ func (p Position) FEN() FEN {
 	}
 
 	// Print Castling
-	castlingStr := ""
-	if p.Castling.whiteShort {
-		castlingStr += "K"
-	}
-	if p.Castling.whiteLong {
-		castlingStr += "Q"
-	}
-	if p.Castling.blackShort {
-		castlingStr += "k"
-	}
-	if p.Castling.blackLong {
-		castlingStr += "q"
-	}
-	if castlingStr == "" {
-		castlingStr = "-"
-	}
+	castlingStr := p.Castling.String()
 
 	// Print En Passant Square
 	enPassantTargetSquareStr := "-"
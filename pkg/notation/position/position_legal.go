@@ -0,0 +1,192 @@
+package position
+
+import (
+	"fmt"
+
+	"gochess/pkg/bitboard"
+	"gochess/pkg/notation/piece"
+	"gochess/pkg/notation/square"
+)
+
+// ValidateMove returns an error describing why the move is not legal in the position,
+// or nil if it is.
+func (p *Position) ValidateMove(from, to square.Square, promotedTo piece.Piece) error {
+	// Check the moving piece
+	pc := p.PieceAt(from)
+	if pc.IsEmpty() {
+		return fmt.Errorf("no piece on %s", from)
+	}
+	if pc.IsWhite() != p.WhitesTurn {
+		return fmt.Errorf("%s on %s can't move, it's the other side's turn", pc, from)
+	}
+	if p.Colored(p.WhitesTurn).Has(to) {
+		return fmt.Errorf("%s can't capture its own piece on %s", pc, to)
+	}
+
+	// Check promotion
+	_, toR := to.FileRank()
+	isPromotion := pc.IsPawn() && (toR == square.Rank8 || toR == square.Rank1)
+	if isPromotion && promotedTo == piece.Piece_None {
+		return fmt.Errorf("pawn move to %s must promote", to)
+	}
+	if !isPromotion && promotedTo != piece.Piece_None {
+		return fmt.Errorf("only pawns reaching the last rank can promote")
+	}
+	if isPromotion && (promotedTo.IsWhite() != pc.IsWhite() || promotedTo.IsPawn() || promotedTo.IsKing()) {
+		return fmt.Errorf("pawn can't promote to %s", promotedTo)
+	}
+
+	// Check the piece can reach the square
+	if pc.IsKing() && isCastlingMove(from, to) {
+		if err := p.validateCastling(from, to); err != nil {
+			return err
+		}
+	} else if !p.canReach(pc, from, to) {
+		return fmt.Errorf("%s on %s can't move to %s", pc, from, to)
+	}
+
+	// Check the move doesn't leave the king in check, a side without a king never being in check
+	p.Make(from, to, promotedTo)
+	kingSquare := p.KingSquare(!p.WhitesTurn)
+	inCheck := kingSquare != square.Square_Invalid && p.IsSquareAttacked(kingSquare, p.WhitesTurn)
+	p.Unmake()
+	if inCheck {
+		return fmt.Errorf("%s%s leaves the king in check", from, to)
+	}
+
+	return nil
+}
+
+func isCastlingMove(from, to square.Square) bool {
+	fromF, fromR := from.FileRank()
+	toF, toR := to.FileRank()
+	return fromF == square.FileE && fromR == toR && (toF == square.FileG || toF == square.FileC) &&
+		(fromR == square.Rank1 || fromR == square.Rank8)
+}
+
+// canReach reports if the piece moves from one square to the other, ignoring checks.
+func (p *Position) canReach(pc piece.Piece, from, to square.Square) bool {
+	switch pc.Abs() {
+	case piece.Piece_Pawn:
+		fromF, fromR := from.FileRank()
+		toF, toR := to.FileRank()
+		forward, startRank := square.Rank(1), square.Rank2
+		if pc.IsBlack() {
+			forward, startRank = -1, square.Rank7
+		}
+
+		// Captures, including en passant
+		if bitboard.PawnAttack(pc.IsWhite(), from).Has(to) {
+			return p.Colored(!pc.IsWhite()).Has(to) || to == p.EnPassantSquare
+		}
+
+		// Pushes into empty squares
+		if fromF != toF || p.Occupied().Has(to) {
+			return false
+		}
+		if toR-fromR == forward {
+			return true
+		}
+		return fromR == startRank && toR-fromR == 2*forward &&
+			!p.Occupied().Has(square.NewSquare(fromF, fromR+forward))
+	case piece.Piece_Knight:
+		return bitboard.KnightAttacks[from].Has(to)
+	case piece.Piece_Bishop:
+		return bitboard.BishopAttacks(from, p.Occupied()).Has(to)
+	case piece.Piece_Rook:
+		return bitboard.RookAttacks(from, p.Occupied()).Has(to)
+	case piece.Piece_Queen:
+		return bitboard.QueenAttacks(from, p.Occupied()).Has(to)
+	case piece.Piece_King:
+		return bitboard.KingAttacks[from].Has(to)
+	default:
+		return false
+	}
+}
+
+func (p *Position) validateCastling(from, to square.Square) error {
+	toF, r := to.FileRank()
+	isShort := toF == square.FileG
+	side := "long"
+	if isShort {
+		side = "short"
+	}
+
+	// Check rights and the rook
+	if !p.CanCastle(p.WhitesTurn, isShort) {
+		return fmt.Errorf("no right to castle %s", side)
+	}
+	rookSquare := square.NewSquare(square.FileA, r)
+	if isShort {
+		rookSquare = square.NewSquare(square.FileH, r)
+	}
+	if !p.PieceAt(rookSquare).IsRook() {
+		return fmt.Errorf("no rook on %s to castle %s with", rookSquare, side)
+	}
+	if bitboard.Between(from, rookSquare)&p.Occupied() != bitboard.Empty {
+		return fmt.Errorf("pieces between king and rook prevent castling %s", side)
+	}
+
+	// Check the king doesn't castle out of or through check
+	if p.IsSquareAttacked(from, !p.WhitesTurn) {
+		return fmt.Errorf("can't castle out of check")
+	}
+	for path := bitboard.Between(from, to); path != bitboard.Empty; {
+		if p.IsSquareAttacked(path.PopLSB(), !p.WhitesTurn) {
+			return fmt.Errorf("can't castle %s through check", side)
+		}
+	}
+	return nil
+}
+
+// HasLegalMove reports if the side to move has at least one legal move.
+func (p *Position) HasLegalMove() bool {
+	own := p.Colored(p.WhitesTurn)
+	for pieces := own; pieces != bitboard.Empty; {
+		from := pieces.PopLSB()
+		pc := p.PieceAt(from)
+		for targets := p.targets(pc, from) &^ own; targets != bitboard.Empty; {
+			to := targets.PopLSB()
+
+			// Any promotion is as legal as the others
+			promotedTo := piece.Piece_None
+			if _, r := to.FileRank(); pc.IsPawn() && (r == square.Rank8 || r == square.Rank1) {
+				promotedTo = piece.Piece_Queen
+				if pc.IsBlack() {
+					promotedTo = -promotedTo
+				}
+			}
+			if p.ValidateMove(from, to, promotedTo) == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// targets returns a superset of the squares the piece can move to, ignoring castling
+// which is only legal if moving the king one square is legal too.
+func (p *Position) targets(pc piece.Piece, from square.Square) bitboard.Bitboard {
+	switch pc.Abs() {
+	case piece.Piece_Pawn:
+		pushes := bitboard.New(from).North()
+		pushes |= pushes.North()
+		if pc.IsBlack() {
+			pushes = bitboard.New(from).South()
+			pushes |= pushes.South()
+		}
+		return bitboard.PawnAttack(pc.IsWhite(), from) | pushes
+	case piece.Piece_Knight:
+		return bitboard.KnightAttacks[from]
+	case piece.Piece_Bishop:
+		return bitboard.BishopAttacks(from, p.Occupied())
+	case piece.Piece_Rook:
+		return bitboard.RookAttacks(from, p.Occupied())
+	case piece.Piece_Queen:
+		return bitboard.QueenAttacks(from, p.Occupied())
+	case piece.Piece_King:
+		return bitboard.KingAttacks[from]
+	default:
+		return bitboard.Empty
+	}
+}
@@ -0,0 +1,180 @@
+package position
+
+import (
+	"slices"
+
+	"gochess/pkg/notation/piece"
+	"gochess/pkg/notation/square"
+)
+
+// undo records the state Make can't recover from the move itself.
+type undo struct {
+	from            square.Square
+	to              square.Square
+	piece           piece.Piece
+	captured        piece.Piece
+	capturedSquare  square.Square
+	castling        Castling
+	enPassantSquare square.Square
+	halfmoveCount   int
+	hash            uint64
+}
+
+// Copy returns a deep copy of the position, including its undo history.
+func (p Position) Copy() *Position {
+	p.PieceList = slices.Clone(p.PieceList)
+	p.undoStack = slices.Clone(p.undoStack)
+	return &p
+}
+
+// Make plays the move in place, moving the rook when castling, removing pawns
+// captured en passant and updating castling rights, en passant square and clocks.
+// The move is expected to be legal in the position.
+func (p *Position) Make(from, to square.Square, promotedTo piece.Piece) {
+	pc := p.PieceAt(from)
+	fromF, fromR := from.FileRank()
+	toF, toR := to.FileRank()
+
+	// Record the state for Unmake
+	u := undo{
+		from:            from,
+		to:              to,
+		piece:           pc,
+		captured:        p.PieceAt(to),
+		capturedSquare:  to,
+		castling:        p.Castling,
+		enPassantSquare: p.EnPassantSquare,
+		halfmoveCount:   p.HalfmoveCount,
+		hash:            p.Hash,
+	}
+
+	// Hash out the en passant square while the pawns that could use it are in place
+	p.Hash ^= p.enPassantHash()
+
+	// Find the pawn captured en passant
+	if pc.IsPawn() && to == p.EnPassantSquare {
+		u.capturedSquare = square.NewSquare(toF, fromR)
+		u.captured = p.PieceAt(u.capturedSquare)
+	}
+
+	// Update pieces
+	p.removePiece(u.capturedSquare)
+	p.removePiece(from)
+	if promotedTo == piece.Piece_None {
+		p.putPiece(to, pc)
+	} else {
+		p.putPiece(to, promotedTo)
+	}
+
+	// Move the rook when castling
+	if pc.IsKing() && (toF-fromF == 2 || fromF-toF == 2) {
+		rookFrom, rookTo := castlingRookSquares(from, to)
+		p.putPiece(rookTo, p.removePiece(rookFrom))
+	}
+
+	// Update SideToMove
+	p.WhitesTurn = !p.WhitesTurn
+	p.Hash ^= zobristWhitesTurn
+
+	// Update Castling, moving the king or a rook or capturing a rook loses rights
+	p.Hash ^= zobristCastling[p.Castling]
+	p.Castling &^= CastlingLostFrom(from) | CastlingLostFrom(to)
+	p.Hash ^= zobristCastling[p.Castling]
+
+	// Update EnPassantSquare
+	p.EnPassantSquare = square.Square_Invalid
+	if pc.IsPawn() && (toR-fromR == 2 || fromR-toR == 2) {
+		p.EnPassantSquare = square.NewSquare(fromF, (fromR+toR)/2)
+		p.Hash ^= p.enPassantHash()
+	}
+
+	// Update HalfmoveCount
+	if pc.IsPawn() || u.captured != piece.Piece_None {
+		p.HalfmoveCount = 0
+	} else {
+		p.HalfmoveCount++
+	}
+
+	// Update FullmoveCount
+	if p.WhitesTurn {
+		p.FullmoveCount++
+	}
+
+	p.undoStack = append(p.undoStack, u)
+}
+
+// MakeNull passes the turn without moving, for the null move pruning of the search.
+// Repetitions aren't looked for before it. The side to move must not be in check.
+func (p *Position) MakeNull() {
+	p.undoStack = append(p.undoStack, undo{
+		from:            square.Square_Invalid,
+		to:              square.Square_Invalid,
+		castling:        p.Castling,
+		enPassantSquare: p.EnPassantSquare,
+		halfmoveCount:   p.HalfmoveCount,
+		hash:            p.Hash,
+	})
+
+	p.Hash ^= p.enPassantHash()
+	p.EnPassantSquare = square.Square_Invalid
+	p.WhitesTurn = !p.WhitesTurn
+	p.Hash ^= zobristWhitesTurn
+	p.HalfmoveCount = 0
+	if p.WhitesTurn {
+		p.FullmoveCount++
+	}
+}
+
+// Unmake takes back the last move played with Make or MakeNull.
+func (p *Position) Unmake() {
+	if len(p.undoStack) == 0 {
+		return
+	}
+	u := p.undoStack[len(p.undoStack)-1]
+	p.undoStack = p.undoStack[:len(p.undoStack)-1]
+
+	// Update FullmoveCount
+	if p.WhitesTurn {
+		p.FullmoveCount--
+	}
+
+	// Update SideToMove
+	p.WhitesTurn = !p.WhitesTurn
+
+	// Nothing moved for a null move
+	if u.piece == piece.Piece_None {
+		p.EnPassantSquare = u.enPassantSquare
+		p.HalfmoveCount = u.halfmoveCount
+		p.Hash = u.hash
+		return
+	}
+
+	// Move the rook back when castling
+	fromF, _ := u.from.FileRank()
+	toF, _ := u.to.FileRank()
+	if u.piece.IsKing() && (toF-fromF == 2 || fromF-toF == 2) {
+		rookFrom, rookTo := castlingRookSquares(u.from, u.to)
+		p.putPiece(rookFrom, p.removePiece(rookTo))
+	}
+
+	// Update pieces
+	p.removePiece(u.to)
+	p.putPiece(u.capturedSquare, u.captured)
+	p.putPiece(u.from, u.piece)
+
+	// Restore the rest of the state
+	p.Castling = u.castling
+	p.EnPassantSquare = u.enPassantSquare
+	p.HalfmoveCount = u.halfmoveCount
+	p.Hash = u.hash
+}
+
+// castlingRookSquares returns where the rook moves from and to for a castling king move.
+func castlingRookSquares(kingFrom, kingTo square.Square) (square.Square, square.Square) {
+	fromF, r := kingFrom.FileRank()
+	toF, _ := kingTo.FileRank()
+	if toF < fromF {
+		return square.NewSquare(square.FileA, r), square.NewSquare(square.FileD, r)
+	}
+	return square.NewSquare(square.FileH, r), square.NewSquare(square.FileF, r)
+}
@@ -2,10 +2,15 @@ package position_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
+	"gochess/pkg/bitboard"
+	"gochess/pkg/notation/piece"
 	"gochess/pkg/notation/position"
+	"gochess/pkg/notation/square"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -16,6 +21,123 @@ func TestNewPosition(t *testing.T) {
 	fmt.Println(startingPosition.String())
 }
 
+func TestPositionBitboards(t *testing.T) {
+	startingPosition, err := position.NewPosition(position.StartingFEN)
+	require.NoError(t, err)
+
+	assert.Equal(t, bitboard.RankMask(square.Rank2), startingPosition.Pieces(piece.Piece_WhitePawn))
+	assert.Equal(t, bitboard.New(square.Square_b8, square.Square_g8), startingPosition.Pieces(piece.Piece_BlackKnight))
+	assert.Equal(t, bitboard.New(square.Square_d1, square.Square_d8), startingPosition.PiecesOfType(piece.Piece_Queen))
+	assert.Equal(t, bitboard.RankMask(square.Rank1)|bitboard.RankMask(square.Rank2), startingPosition.Colored(true))
+	assert.Equal(t, 32, startingPosition.Occupied().Count())
+
+	assert.Equal(t, square.Square_e8, startingPosition.KingSquare(false))
+	assert.False(t, startingPosition.InCheck())
+	assert.True(t, startingPosition.IsSquareAttacked(square.Square_f3, true))
+	assert.False(t, startingPosition.IsSquareAttacked(square.Square_e4, true))
+}
+
+func TestPositionHash(t *testing.T) {
+	startingPosition, err := position.NewPosition(position.StartingFEN)
+	require.NoError(t, err)
+	startingHash := startingPosition.Hash
+
+	// Transpose back to the starting position
+	startingPosition.Make(square.Square_g1, square.Square_f3, piece.Piece_None)
+	assert.NotEqual(t, startingHash, startingPosition.Hash)
+	startingPosition.Make(square.Square_g8, square.Square_f6, piece.Piece_None)
+	startingPosition.Make(square.Square_f3, square.Square_g1, piece.Piece_None)
+	startingPosition.Make(square.Square_f6, square.Square_g8, piece.Piece_None)
+	assert.Equal(t, startingHash, startingPosition.Hash)
+
+	tests := []struct {
+		name  string
+		fen1  position.FEN
+		fen2  position.FEN
+		equal bool
+	}{
+		{"Clocks", position.StartingFEN, "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 7 20", true},
+		{"Side To Move", position.StartingFEN, "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR b KQkq - 0 1", false},
+		{"Castling", position.StartingFEN, "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w Kkq - 0 1", false},
+		{"Unusable En Passant", "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+			"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1", true},
+		{"Usable En Passant", "rnbqkbnr/ppp1pppp/8/8/3pP3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+			"rnbqkbnr/ppp1pppp/8/8/3pP3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p1, err := position.NewPosition(test.fen1)
+			require.NoError(t, err)
+			p2, err := position.NewPosition(test.fen2)
+			require.NoError(t, err)
+			assert.Equal(t, test.equal, p1.Hash == p2.Hash)
+		})
+	}
+}
+
+func TestPositionPawnHash(t *testing.T) {
+	p, err := position.NewPosition("4k3/1P6/8/3pP3/8/8/8/4K3 w - d6 0 1")
+	require.NoError(t, err)
+	startingPawnHash := p.PawnHash
+
+	// Only pawn moves change it, and Unmake restores it
+	p.Make(square.Square_e1, square.Square_e2, piece.Piece_None)
+	assert.Equal(t, startingPawnHash, p.PawnHash)
+	p.Unmake()
+	for _, m := range []struct {
+		from, to square.Square
+		promo    piece.Piece
+	}{
+		{square.Square_e5, square.Square_d6, piece.Piece_None},
+		{square.Square_b7, square.Square_b8, piece.Piece_Queen},
+	} {
+		p.Make(m.from, m.to, m.promo)
+		assert.NotEqual(t, startingPawnHash, p.PawnHash)
+		expected, err := position.NewPosition(p.FEN())
+		require.NoError(t, err)
+		assert.Equal(t, expected.PawnHash, p.PawnHash)
+		p.Unmake()
+		assert.Equal(t, startingPawnHash, p.PawnHash)
+	}
+
+	// Pieces don't count
+	other, err := position.NewPosition("r3k3/1P6/8/3pP3/8/8/8/2B1K3 w - - 0 1")
+	require.NoError(t, err)
+	assert.Equal(t, startingPawnHash, other.PawnHash)
+}
+
+func TestPositionNullMove(t *testing.T) {
+	fen := position.FEN("rnbqkbnr/ppp1pppp/8/8/3pP3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 3")
+	p, err := position.NewPosition(fen)
+	require.NoError(t, err)
+	hash := p.Hash
+
+	// The turn passes and the en passant square is lost
+	p.MakeNull()
+	assert.Equal(t, position.FEN("rnbqkbnr/ppp1pppp/8/8/3pP3/8/PPPP1PPP/RNBQKBNR w KQkq - 0 4"), p.FEN())
+	expected, err := position.NewPosition(p.FEN())
+	require.NoError(t, err)
+	assert.Equal(t, expected.Hash, p.Hash)
+
+	p.Unmake()
+	assert.Equal(t, fen, p.FEN())
+	assert.Equal(t, hash, p.Hash)
+}
+
+func TestPositionValidateMove(t *testing.T) {
+	p, err := position.NewPosition("4k3/8/8/8/8/8/4P3/4K2r w - - 0 1")
+	require.NoError(t, err)
+	assert.NoError(t, p.ValidateMove(square.Square_e1, square.Square_d2, piece.Piece_None))
+	assert.ErrorContains(t, p.ValidateMove(square.Square_e2, square.Square_e3, piece.Piece_None), "e2e3 leaves the king in check")
+	assert.True(t, p.HasLegalMove())
+
+	// A side without a king is never in check
+	p, err = position.NewPosition("8/8/8/8/8/8/4P3/8 w - - 0 1")
+	require.NoError(t, err)
+	assert.NoError(t, p.ValidateMove(square.Square_e2, square.Square_e3, piece.Piece_None))
+	assert.True(t, p.HasLegalMove())
+}
+
 func BenchmarkPosition(b *testing.B) {
 	b.Run("Just New", func(b *testing.B) {
 		for n := 0; n < b.N; n++ {
@@ -23,3 +145,21 @@ func BenchmarkPosition(b *testing.B) {
 		}
 	})
 }
+
+func TestPositionAsciiString(t *testing.T) {
+	p, err := position.NewPosition("4k3/8/8/8/8/8/8/R3K3 w - - 0 1")
+	require.NoError(t, err)
+
+	// White at the bottom
+	lines := strings.Split(p.AsciiString(), "\n")
+	assert.Equal(t, " |   |   |   |   | k |   |   |   | 8", lines[2])
+	assert.Equal(t, " | R |   |   |   | K |   |   |   | 1", lines[16])
+	assert.Equal(t, "   a   b   c   d   e   f   g   h ", lines[18])
+	assert.Equal(t, "FEN: 4k3/8/8/8/8/8/8/R3K3 w - - 0 1", lines[20])
+
+	// Black at the bottom
+	lines = strings.Split(p.AsciiStringFrom(false), "\n")
+	assert.Equal(t, " |   |   |   | K |   |   |   | R | 1", lines[2])
+	assert.Equal(t, " |   |   |   | k |   |   |   |   | 8", lines[16])
+	assert.Equal(t, "   h   g   f   e   d   c   b   a ", lines[18])
+}
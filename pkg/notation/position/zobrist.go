@@ -0,0 +1,85 @@
+package position
+
+import (
+	"gochess/pkg/bitboard"
+	"gochess/pkg/notation/piece"
+	"gochess/pkg/notation/square"
+)
+
+// Random keys xor-ed together into a position's Zobrist hash
+var (
+	zobristPieces        [13][64]uint64
+	zobristWhitesTurn    uint64
+	zobristCastling      [16]uint64
+	zobristEnPassantFile [8]uint64
+)
+
+func init() {
+	// Fixed seed so hashes are the same on every run
+	rng := uint64(0x9E3779B97F4A7C15)
+	next := func() uint64 {
+		rng ^= rng >> 12
+		rng ^= rng << 25
+		rng ^= rng >> 27
+		return rng * 2685821657736338717
+	}
+
+	for pc := range zobristPieces {
+		for s := range zobristPieces[pc] {
+			zobristPieces[pc][s] = next()
+		}
+	}
+	zobristWhitesTurn = next()
+	for c := range zobristCastling {
+		zobristCastling[c] = next()
+	}
+	for f := range zobristEnPassantFile {
+		zobristEnPassantFile[f] = next()
+	}
+}
+
+func zobristPiece(pc piece.Piece, s square.Square) uint64 {
+	return zobristPieces[pc+piece.Piece_King][s]
+}
+
+// computeHash builds the Zobrist hash of the position from scratch.
+func (p *Position) computeHash() uint64 {
+	var hash uint64
+	for occupied := p.Occupied(); occupied != bitboard.Empty; {
+		s := occupied.PopLSB()
+		hash ^= zobristPiece(p.PieceAt(s), s)
+	}
+	if p.WhitesTurn {
+		hash ^= zobristWhitesTurn
+	}
+	hash ^= zobristCastling[p.Castling]
+	hash ^= p.enPassantHash()
+	return hash
+}
+
+// computePawnHash builds the Zobrist hash of the pawns from scratch.
+func (p *Position) computePawnHash() uint64 {
+	var hash uint64
+	for pawns := p.PiecesOfType(piece.Piece_Pawn); pawns != bitboard.Empty; {
+		s := pawns.PopLSB()
+		hash ^= zobristPiece(p.PieceAt(s), s)
+	}
+	return hash
+}
+
+// enPassantHash is only non zero when a pawn of the side to move can capture en passant,
+// so positions that only differ by an unusable en passant square hash the same.
+func (p *Position) enPassantHash() uint64 {
+	if p.EnPassantSquare == square.Square_Invalid {
+		return 0
+	}
+	pawn := piece.Piece_WhitePawn
+	if !p.WhitesTurn {
+		pawn = piece.Piece_BlackPawn
+	}
+	if bitboard.PawnAttack(!p.WhitesTurn, p.EnPassantSquare)&p.Pieces(pawn) == bitboard.Empty {
+		return 0
+	}
+	f, _ := p.EnPassantSquare.FileRank()
+	return zobristEnPassantFile[f]
+}
@@ -42,28 +42,24 @@ func SquaresInBetween(fromSquare, toSquare Square) []Square {
 
 	// On the positive diagonal
 	if int(fromR-toR) == int(fromF-toF) {
-		lowerR, _ := fromR, toR
-		lowerF, higherF := fromF, toF
+		lowerR, lowerF, higherF := fromR, fromF, toF
 		if fromF > toF {
-			lowerR, _ = toR, fromR
-			lowerF, higherF = toF, fromF
+			lowerR, lowerF, higherF = toR, toF, fromF
 		}
 		for p := 1; p < int(higherF-lowerF); p++ {
-			squaresInBetween = append(squaresInBetween, NewSquare(lowerF+File(p), lowerR-Rank(p)))
+			squaresInBetween = append(squaresInBetween, NewSquare(lowerF+File(p), lowerR+Rank(p)))
 		}
 		return squaresInBetween
 	}
 
 	// On the negative diagonal
 	if int(fromR-toR) == -int(fromF-toF) {
-		lowerR, _ := fromR, toR
-		lowerF, higherF := fromF, toF
+		higherR, lowerF, higherF := fromR, fromF, toF
 		if fromF > toF {
-			lowerR, _ = toR, fromR
-			lowerF, higherF = toF, fromF
+			higherR, lowerF, higherF = toR, toF, fromF
 		}
 		for p := 1; p < int(higherF-lowerF); p++ {
-			squaresInBetween = append(squaresInBetween, NewSquare(lowerF+File(p), lowerR-Rank(p)))
+			squaresInBetween = append(squaresInBetween, NewSquare(lowerF+File(p), higherR-Rank(p)))
 		}
 		return squaresInBetween
 	}
@@ -77,9 +73,9 @@ func SquaresInBetween(fromSquare, toSquare Square) []Square {
 
 type Square int
 
-const (
-	Square_Invalid Square = -1
+const Square_Invalid Square = -1
 
+const (
 	Square_a1 Square = iota
 	Square_b1
 	Square_c1
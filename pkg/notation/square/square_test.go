@@ -37,26 +37,29 @@ func TestSquaresInBetween(t *testing.T) {
 		s1       square.Square
 		s2       square.Square
 		notEmpty bool
+		squares  []square.Square
 	}{
-		{"Same Square", square.Square_a1, square.Square_a1, false},
+		{"Same Square", square.Square_a1, square.Square_a1, false, nil},
 
-		{"Same File", square.Square_a1, square.Square_a2, false},
-		{"Same File", square.Square_a1, square.Square_a3, true},
-		{"Same File", square.Square_a3, square.Square_a1, true},
+		{"Same File", square.Square_a1, square.Square_a2, false, nil},
+		{"Same File", square.Square_a1, square.Square_a3, true, []square.Square{square.Square_a2}},
+		{"Same File", square.Square_a3, square.Square_a1, true, []square.Square{square.Square_a2}},
 
-		{"Same Rank", square.Square_a1, square.Square_b1, false},
-		{"Same Rank", square.Square_a1, square.Square_c1, true},
-		{"Same Rank", square.Square_c1, square.Square_a1, true},
+		{"Same Rank", square.Square_a1, square.Square_b1, false, nil},
+		{"Same Rank", square.Square_a1, square.Square_c1, true, []square.Square{square.Square_b1}},
+		{"Same Rank", square.Square_c1, square.Square_a1, true, []square.Square{square.Square_b1}},
 
-		{"Positive Diagonal", square.Square_a1, square.Square_b2, false},
-		{"Positive Diagonal", square.Square_a1, square.Square_c3, true},
-		{"Positive Diagonal", square.Square_c3, square.Square_a1, true},
+		{"Positive Diagonal", square.Square_a1, square.Square_b2, false, nil},
+		{"Positive Diagonal", square.Square_a1, square.Square_c3, true, []square.Square{square.Square_b2}},
+		{"Positive Diagonal", square.Square_c3, square.Square_a1, true, []square.Square{square.Square_b2}},
+		{"Positive Diagonal", square.Square_b2, square.Square_e5, true, []square.Square{square.Square_c3, square.Square_d4}},
 
-		{"Negative Diagonal", square.Square_a3, square.Square_b2, false},
-		{"Negative Diagonal", square.Square_a3, square.Square_c1, true},
-		{"Negative Diagonal", square.Square_c1, square.Square_a3, true},
+		{"Negative Diagonal", square.Square_a3, square.Square_b2, false, nil},
+		{"Negative Diagonal", square.Square_a3, square.Square_c1, true, []square.Square{square.Square_b2}},
+		{"Negative Diagonal", square.Square_c1, square.Square_a3, true, []square.Square{square.Square_b2}},
+		{"Negative Diagonal", square.Square_h1, square.Square_e4, true, []square.Square{square.Square_f3, square.Square_g2}},
 
-		{"None", square.Square_a1, square.Square_b3, false},
+		{"None", square.Square_a1, square.Square_b3, false, nil},
 	}
 
 	for _, test := range tests {
@@ -65,6 +68,7 @@ func TestSquaresInBetween(t *testing.T) {
 			fmt.Printf("%s - %s : %s\n", test.s1, test.s2, squares)
 			if test.notEmpty {
 				assert.NotEmpty(t, squares)
+				assert.ElementsMatch(t, test.squares, squares)
 			} else {
 				assert.Empty(t, squares)
 			}
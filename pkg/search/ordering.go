@@ -0,0 +1,258 @@
+package search
+
+import (
+	"gochess/pkg/generation"
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/piece"
+	"gochess/pkg/notation/position"
+)
+
+// maxHistory bounds the history scores, bonuses shrink as scores get near it.
+const maxHistory = 16384
+
+// ==================== Heuristics ====================
+
+// heuristics remember the quiet moves that refuted other moves.
+type heuristics struct {
+	killers  [MaxPly + 1][2]move.Compact
+	history  [2][64][64]int                           // By color, from and to squares
+	counters [2*piece.Piece_King + 1][64]move.Compact // By previous piece and its to square
+}
+
+func (h *heuristics) clear() {
+	*h = heuristics{}
+}
+
+// age forgets the killers of the previous search and fades its history.
+func (h *heuristics) age() {
+	h.killers = [MaxPly + 1][2]move.Compact{}
+	for c := range h.history {
+		for from := range h.history[c] {
+			for to := range h.history[c][from] {
+				h.history[c][from][to] /= 2
+			}
+		}
+	}
+}
+
+func (h *heuristics) historyOf(m *move.Move) *int {
+	c := 0
+	if m.Piece.IsBlack() {
+		c = 1
+	}
+	return &h.history[c][m.From][m.To]
+}
+
+func (h *heuristics) counterOf(prev *move.Move) *move.Compact {
+	return &h.counters[prev.Piece+piece.Piece_King][prev.To]
+}
+
+// addHistory adds the bonus to the history of the move, with a malus when negative.
+func (h *heuristics) addHistory(m *move.Move, bonus int) {
+	score := h.historyOf(m)
+	*score += bonus - *score*max(bonus, -bonus)/maxHistory
+}
+
+// update rewards the quiet move that caused a cutoff and punishes the quiet moves tried before it.
+func (h *heuristics) update(best *move.Move, tried []*move.Move, prev *move.Move, ply, depth int) {
+	c := best.Compact()
+	if h.killers[ply][0] != c {
+		h.killers[ply][1], h.killers[ply][0] = h.killers[ply][0], c
+	}
+	if prev != nil {
+		*h.counterOf(prev) = c
+	}
+
+	bonus := min(depth*depth, maxHistory/4)
+	h.addHistory(best, bonus)
+	for _, m := range tried {
+		h.addHistory(m, -bonus)
+	}
+}
+
+func isQuiet(m *move.Move) bool {
+	return !m.IsCapture && m.PromotedTo == piece.Piece_None
+}
+
+// ==================== Move Picker ====================
+
+type stage int
+
+const (
+	stage_HashMove stage = iota
+	stage_GenerateNoisy
+	stage_GoodNoisy
+	stage_Refutations // Killers and countermove
+	stage_GenerateQuiet
+	stage_Quiet
+	stage_BadNoisy
+	stage_Done
+)
+
+// movePicker hands out the legal moves best first, generating them in stages: the hash move,
+// captures and promotions winning material, killers and countermove, quiet moves by history,
+// and the captures losing material last.
+type movePicker struct {
+	p         *position.Position
+	h         *heuristics
+	stage     stage
+	noisyOnly bool // Only captures and promotions that don't lose material
+
+	hashMove    move.Move
+	refutations []move.Move
+	refutation  int // Index of the next refutation
+	moves       []scoredMove
+	bad         []*move.Move
+	lastScore   int // Ordering score of the last move handed out, 0 unless picked from the scored ones
+}
+
+// scoredMove is a move with its ordering score.
+type scoredMove struct {
+	move  *move.Move
+	score int
+}
+
+func newMovePicker(p *position.Position, h *heuristics, hashMove move.Compact, ply int, prev *move.Move, noisyOnly bool) *movePicker {
+	mp := &movePicker{p: p, h: h, noisyOnly: noisyOnly}
+	if m, err := move.NewMoveFromCompact(p, hashMove); err == nil && (!noisyOnly || !isQuiet(&m)) {
+		mp.hashMove = m
+	}
+	if noisyOnly {
+		return mp
+	}
+
+	// Keep the legal quiet refutations not already tried
+	candidates := []move.Compact{h.killers[ply][0], h.killers[ply][1]}
+	if prev != nil {
+		candidates = append(candidates, *h.counterOf(prev))
+	}
+	for _, c := range candidates {
+		m, err := move.NewMoveFromCompact(p, c)
+		if err != nil || !isQuiet(&m) || mp.isTried(&m) {
+			continue
+		}
+		mp.refutations = append(mp.refutations, m)
+	}
+	return mp
+}
+
+// isTried reports if the move was handed out before its stage.
+func (mp *movePicker) isTried(m *move.Move) bool {
+	if *m == mp.hashMove {
+		return true
+	}
+	for i := range mp.refutations {
+		if *m == mp.refutations[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// next returns the next best move, or nil when all were handed out.
+func (mp *movePicker) next() *move.Move {
+	for {
+		switch mp.stage {
+		case stage_HashMove:
+			mp.stage++
+			if mp.hashMove != (move.Move{}) {
+				mp.lastScore = 0
+				return &mp.hashMove
+			}
+		case stage_GenerateNoisy:
+			mp.stage++
+			mp.score(generation.GenerateMovesOfKind(mp.p, generation.MoveKind_Noisy), mp.mvvLva)
+		case stage_GoodNoisy:
+			m := mp.pick()
+			if m == nil {
+				mp.stage++
+				if mp.noisyOnly {
+					mp.stage = stage_Done
+				}
+				continue
+			}
+
+			// Captures losing material wait for the end
+			if generation.SEE(mp.p, *m) < 0 {
+				if !mp.noisyOnly {
+					mp.bad = append(mp.bad, m)
+				}
+				continue
+			}
+			return m
+		case stage_Refutations:
+			if mp.refutation < len(mp.refutations) {
+				mp.refutation++
+				mp.lastScore = 0
+				return &mp.refutations[mp.refutation-1]
+			}
+			mp.stage++
+		case stage_GenerateQuiet:
+			mp.stage++
+			mp.score(generation.GenerateMovesOfKind(mp.p, generation.MoveKind_Quiet), func(m *move.Move) int {
+				return *mp.h.historyOf(m)
+			})
+		case stage_Quiet:
+			if m := mp.pick(); m != nil {
+				return m
+			}
+			mp.stage++
+		case stage_BadNoisy:
+			if len(mp.bad) > 0 {
+				m := mp.bad[0]
+				mp.bad = mp.bad[1:]
+				mp.lastScore = 0
+				return m
+			}
+			mp.stage++
+		default:
+			return nil
+		}
+	}
+}
+
+// score sets the moves to pick from, leaving out those already handed out.
+func (mp *movePicker) score(moves move.MoveList, scoreOf func(*move.Move) int) {
+	mp.moves = mp.moves[:0]
+	for _, m := range moves {
+		if m.Matches(mp.hashMove.Compact()) {
+			continue
+		}
+		if !mp.noisyOnly && isQuiet(m) && mp.isTried(m) {
+			continue
+		}
+		mp.moves = append(mp.moves, scoredMove{m, scoreOf(m)})
+	}
+}
+
+// pick removes and returns the best scored move left, or nil.
+func (mp *movePicker) pick() *move.Move {
+	if len(mp.moves) == 0 {
+		return nil
+	}
+	best := 0
+	for i := range mp.moves {
+		if mp.moves[i].score > mp.moves[best].score {
+			best = i
+		}
+	}
+	m := mp.moves[best].move
+	mp.lastScore = mp.moves[best].score
+	mp.moves[best] = mp.moves[len(mp.moves)-1]
+	mp.moves = mp.moves[:len(mp.moves)-1]
+	return m
+}
+
+// mvvLva scores captures by the most valuable victim then the least valuable attacker,
+// promotions counting as capturing the promoted piece.
+func (mp *movePicker) mvvLva(m *move.Move) int {
+	victim := mp.p.PieceAt(m.To).Abs()
+	if m.Piece.IsPawn() && m.IsCapture && victim == piece.Piece_None {
+		victim = piece.Piece_Pawn
+	}
+	score := 8*int(victim) - int(m.Piece.Abs())
+	if m.PromotedTo != piece.Piece_None {
+		score += 8 * int(m.PromotedTo.Abs())
+	}
+	return score
+}
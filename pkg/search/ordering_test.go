@@ -0,0 +1,90 @@
+package search
+
+import (
+	"testing"
+
+	"gochess/pkg/generation"
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/position"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const kiwipeteFEN = "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1"
+
+// pickAll returns the PCN of the moves in the order the picker hands them out.
+func pickAll(mp *movePicker) []move.PCN {
+	var moves []move.PCN
+	for m := mp.next(); m != nil; m = mp.next() {
+		moves = append(moves, m.PCN())
+	}
+	return moves
+}
+
+func TestMovePicker(t *testing.T) {
+	p, err := position.NewPosition(kiwipeteFEN)
+	require.NoError(t, err)
+
+	hashMove, err := move.NewMoveFromPCN(p, "a2a3")
+	require.NoError(t, err)
+	killer, err := move.NewMoveFromPCN(p, "e1d1")
+	require.NoError(t, err)
+	var h heuristics
+	h.update(&killer, nil, nil, 3, 4)
+
+	// Every legal move exactly once, hash move then winning captures then killer
+	moves := pickAll(newMovePicker(p, &h, hashMove.Compact(), 3, nil, false))
+	var legal []move.PCN
+	for _, m := range generation.GenerateMoves(p) {
+		legal = append(legal, m.PCN())
+	}
+	assert.ElementsMatch(t, legal, moves)
+	assert.Equal(t, move.PCN("a2a3"), moves[0])
+	assert.Equal(t, move.PCN("e2a6"), moves[1]) // Bishop takes bishop
+	assert.Contains(t, moves[:9], move.PCN("e1d1"))
+
+	// Only the moves picked from the scored ones have an ordering score
+	mp := newMovePicker(p, &h, hashMove.Compact(), 3, nil, false)
+	for m := mp.next(); m != nil; m = mp.next() {
+		if *m == hashMove || *m == killer || mp.stage == stage_BadNoisy {
+			assert.Zero(t, mp.lastScore, m.PCN())
+		}
+	}
+
+	// Captures losing material come last
+	assert.ElementsMatch(t, []move.PCN{"f3f6", "f3h3", "e5d7", "e5f7", "e5g6"}, moves[len(moves)-5:])
+
+	// Only captures that don't lose material in quiescence
+	moves = pickAll(newMovePicker(p, &h, move.Compact_None, 3, nil, true))
+	assert.ElementsMatch(t, []move.PCN{"e2a6", "d5e6", "g2h3"}, moves)
+	assert.Equal(t, move.PCN("e2a6"), moves[0])
+}
+
+func TestHeuristics(t *testing.T) {
+	p, err := position.NewPosition(kiwipeteFEN)
+	require.NoError(t, err)
+	m1, err := move.NewMoveFromPCN(p, "a2a3")
+	require.NoError(t, err)
+	m2, err := move.NewMoveFromPCN(p, "a2a4")
+	require.NoError(t, err)
+	prev, err := move.NewMoveFromPCN(p, "d2c1")
+	require.NoError(t, err)
+
+	var h heuristics
+	h.update(&m1, []*move.Move{&m2}, &prev, 2, 3)
+	h.update(&m2, nil, nil, 2, 3)
+	assert.Equal(t, [2]move.Compact{m2.Compact(), m1.Compact()}, h.killers[2])
+	assert.Equal(t, m1.Compact(), *h.counterOf(&prev))
+	assert.Positive(t, *h.historyOf(&m1))
+
+	// History stays bounded
+	for i := 0; i < 1000; i++ {
+		h.update(&m1, nil, nil, 2, 100)
+	}
+	assert.LessOrEqual(t, *h.historyOf(&m1), maxHistory)
+
+	h.age()
+	assert.Equal(t, [2]move.Compact{}, h.killers[2])
+	assert.LessOrEqual(t, *h.historyOf(&m1), maxHistory/2)
+}
@@ -0,0 +1,70 @@
+package search
+
+import (
+	"math"
+
+	"gochess/pkg/notation/piece"
+	"gochess/pkg/notation/position"
+)
+
+// Options switch the selective search techniques, to measure what they are worth in matches.
+type Options struct {
+	NullMove           bool // Null move pruning
+	LateMoveReductions bool
+	ReverseFutility    bool // Reverse futility pruning
+	Futility           bool // Futility pruning
+	CheckExtensions    bool
+}
+
+// DefaultOptions switch every technique on.
+var DefaultOptions = Options{
+	NullMove:           true,
+	LateMoveReductions: true,
+	ReverseFutility:    true,
+	Futility:           true,
+	CheckExtensions:    true,
+}
+
+const (
+	nullMoveMinDepth        = 3
+	reverseFutilityMaxDepth = 6
+	reverseFutilityMargin   = 80 // By depth
+	futilityMaxDepth        = 3
+	futilityMargin          = 120 // By depth
+	lmrMinDepth             = 3
+	lmrMinMoves             = 4 // Moves searched at full depth first
+	lmrHistoryDivisor       = 8192
+)
+
+// lmrReductions are the late move reductions by depth and move number.
+var lmrReductions [64][64]int
+
+func init() {
+	for depth := 1; depth < 64; depth++ {
+		for moves := 1; moves < 64; moves++ {
+			lmrReductions[depth][moves] = int(0.75 + math.Log(float64(depth))*math.Log(float64(moves))/2.25)
+		}
+	}
+}
+
+// lmrReduction returns how much less deep a late quiet move is searched. Moves with a
+// good history and those searched in a principal variation are reduced less.
+func lmrReduction(depth, moves, history int, isPV bool) int {
+	r := lmrReductions[min(depth, 63)][min(moves, 63)]
+	r -= history / lmrHistoryDivisor
+	if isPV {
+		r--
+	}
+	return min(max(r, 0), depth-2)
+}
+
+// hasNonPawnMaterial reports if the side has a piece other than pawns and king. Without
+// one, zugzwang is likely and passing the turn is no measure of the position.
+func hasNonPawnMaterial(p *position.Position, isWhite bool) bool {
+	for _, pc := range p.PieceList {
+		if pc != piece.Piece_None && pc.IsWhite() == isWhite && !pc.IsPawn() && !pc.IsKing() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package search
+
+import "fmt"
+
+// MaxPly is the deepest ply a search can reach.
+const MaxPly = 128
+
+// Score is the value of a position in centipawns for the side to move.
+// Mates are scored away from Score_Mate by the number of plies to reach them.
+type Score int
+
+const (
+	Score_Draw     Score = 0
+	Score_Mate     Score = 32000
+	Score_Infinite Score = Score_Mate + 1
+
+	// Scores beyond are mates
+	Score_MateBound Score = Score_Mate - MaxPly
+)
+
+// MateIn returns the score of mating in the number of plies.
+func MateIn(ply int) Score { return Score_Mate - Score(ply) }
+
+// MatedIn returns the score of being mated in the number of plies.
+func MatedIn(ply int) Score { return -Score_Mate + Score(ply) }
+
+func (s Score) IsMate() bool { return s >= Score_MateBound || s <= -Score_MateBound }
+
+// MateMoves returns the number of moves to mate, negative when being mated.
+func (s Score) MateMoves() int {
+	if s > 0 {
+		return int(Score_Mate-s+1) / 2
+	}
+	return -int(Score_Mate+s) / 2
+}
+
+// String returns the score as in UCI info lines, in centipawns or moves to mate.
+func (s Score) String() string {
+	if s.IsMate() {
+		return fmt.Sprintf("mate %d", s.MateMoves())
+	}
+	return fmt.Sprintf("cp %d", s)
+}
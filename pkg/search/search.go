@@ -0,0 +1,492 @@
+package search
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gochess/pkg/evaluation"
+	"gochess/pkg/generation"
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/position"
+)
+
+const (
+	MaxThreads = 256
+
+	// checkInterval is the number of nodes between checks for cancellation
+	checkInterval = 2048
+
+	// publishInterval is the number of nodes between updates of the count seen by the other threads
+	publishInterval = 256
+)
+
+// Limits stop the search besides the context, zero meaning no limit.
+type Limits struct {
+	Depth    int
+	Nodes    int
+	MoveTime time.Duration // Time to think about the move
+
+	// Clock of the side to move
+	Time      time.Duration
+	Inc       time.Duration
+	MovesToGo int // Moves until the next time control, 0 in sudden death
+
+	// PonderHit is closed when the opponent plays the move pondered on, the clock only
+	// starting then. Nil when not pondering.
+	PonderHit <-chan struct{}
+}
+
+// Info describes a completed iteration of the search.
+type Info struct {
+	Depth    int
+	Score    Score
+	Nodes    int
+	Time     time.Duration
+	PV       []move.Move
+	Hashfull int // Permill of the transposition table used
+}
+
+// Result is the outcome of the deepest completed iteration.
+type Result struct {
+	Move  *move.Move // Nil when there is no legal move
+	Score Score
+	Depth int
+	Nodes int
+	PV    []move.Move
+}
+
+// Searcher looks for the best move with an iterative deepening alpha-beta search, run by
+// several threads sharing the transposition table when asked to (Lazy SMP).
+type Searcher struct {
+	// OnInfo is called after each iteration completed by the main thread
+	OnInfo func(Info)
+
+	// TT is kept from one search to the next
+	TT *TranspositionTable
+
+	// Evaluator scores the positions at the leaves, the helper threads use its parameters and terms
+	Evaluator *evaluation.Evaluator
+
+	// Options switch the selective search techniques
+	Options Options
+
+	// Threads is the number of threads searching together
+	Threads int
+
+	// MoveOverhead is the time lost on each move communicating with the GUI
+	MoveOverhead time.Duration
+
+	ctx     context.Context
+	limits  Limits
+	start   time.Time
+	tm      timeManager // Only used by the main thread
+	stop    atomic.Bool
+	workers []*worker
+}
+
+func NewSearcher() *Searcher {
+	return &Searcher{
+		TT:           NewTranspositionTable(DefaultHashMB),
+		Evaluator:    evaluation.NewEvaluator(),
+		Options:      DefaultOptions,
+		Threads:      1,
+		MoveOverhead: DefaultMoveOverhead,
+	}
+}
+
+// Search searches the position until the limits are reached or the context is done,
+// and returns the best move found. The position is left as it was.
+func (s *Searcher) Search(ctx context.Context, p *position.Position, limits Limits) Result {
+	s.ctx, s.limits = ctx, limits
+	s.start = time.Now()
+	s.stop.Store(false)
+	s.TT.NewSearch()
+
+	// Fall back on any legal move if not even the first iteration completes
+	moves := generation.GenerateMoves(p)
+	if len(moves) == 0 {
+		return Result{}
+	}
+	s.tm.init(limits, s.MoveOverhead, len(moves))
+
+	// The helpers search copies of the position until the main thread is done
+	s.setupWorkers()
+	var wg sync.WaitGroup
+	for _, w := range s.workers[1:] {
+		wg.Add(1)
+		go func(w *worker, p *position.Position) {
+			defer wg.Done()
+			w.iterate(p, moves[0])
+		}(w, p.Copy())
+	}
+	s.workers[0].iterate(p, moves[0])
+	s.stop.Store(true)
+	wg.Wait()
+
+	// Keep the deepest result, the best of those as deep
+	result := s.workers[0].result
+	for _, w := range s.workers[1:] {
+		if r := w.result; r.Depth > result.Depth || r.Depth == result.Depth && r.Score > result.Score {
+			result = r
+		}
+	}
+	result.Nodes = s.workers[0].totalNodes()
+	return result
+}
+
+// setupWorkers makes as many workers as threads, keeping those of the previous search.
+func (s *Searcher) setupWorkers() {
+	threads := min(max(s.Threads, 1), MaxThreads)
+	for len(s.workers) < threads {
+		w := &worker{Searcher: s, id: len(s.workers), evaluator: s.Evaluator}
+		if w.id > 0 {
+			w.evaluator = evaluation.NewEvaluator()
+		}
+		s.workers = append(s.workers, w)
+	}
+	s.workers = s.workers[:threads]
+
+	// Forget the nodes of the previous search before any thread counts them
+	for _, w := range s.workers {
+		w.nodes, w.stopped = 0, false
+		w.published.Store(0)
+	}
+
+	// The helpers evaluate alike
+	for _, w := range s.workers[1:] {
+		if w.evaluator.Params != s.Evaluator.Params {
+			w.evaluator.SetParams(s.Evaluator.Params)
+		}
+		w.evaluator.Terms = s.Evaluator.Terms
+	}
+}
+
+// Clear forgets everything learned in previous searches, for a new game.
+func (s *Searcher) Clear() {
+	s.TT.Clear()
+	for _, w := range s.workers {
+		w.heuristics.clear()
+	}
+}
+
+// ==================== Worker ====================
+
+// worker is a thread of the search with its own state.
+type worker struct {
+	*Searcher
+	id        int // The main thread is 0, the helpers follow
+	evaluator *evaluation.Evaluator
+	result    Result // Deepest iteration completed
+
+	nodes     int
+	published atomic.Int64 // Nodes, published to the other threads every publishInterval nodes
+	stopped   bool
+
+	// Triangular principal variation table
+	pv    [MaxPly + 1][MaxPly + 1]move.Move
+	pvLen [MaxPly + 1]int
+
+	// Move ordering
+	heuristics heuristics
+	moveStack  [MaxPly + 1]*move.Move // Move played at each ply
+}
+
+// iterate searches deeper and deeper until stopped. Helpers start deeper than the main
+// thread, half of them by a ply, so the threads don't all search the same tree.
+func (w *worker) iterate(p *position.Position, fallback *move.Move) {
+	w.heuristics.age()
+	w.result = Result{Move: fallback}
+
+	maxDepth := w.limits.Depth
+	if maxDepth <= 0 || maxDepth > MaxPly {
+		maxDepth = MaxPly
+	}
+	for depth := 1 + w.id%2; depth <= maxDepth; depth++ {
+		score := w.negamax(p, depth, 0, -Score_Infinite, Score_Infinite, w.result.PV)
+		if w.stopped {
+			break
+		}
+
+		// Keep the completed iteration
+		pv := make([]move.Move, w.pvLen[0])
+		copy(pv, w.pv[0][:w.pvLen[0]])
+		w.result = Result{Move: &pv[0], Score: score, Depth: depth, PV: pv}
+		if w.id == 0 && w.OnInfo != nil {
+			w.OnInfo(Info{Depth: depth, Score: score, Nodes: w.totalNodes(), Time: time.Since(w.start), PV: pv,
+				Hashfull: w.TT.Hashfull()})
+		}
+
+		// A forced mate won't get shorter with more depth
+		if score.IsMate() && int(Score_Mate-max(score, -score)) <= depth {
+			break
+		}
+		if w.id == 0 && w.tm.softStop(w.result) {
+			break
+		}
+	}
+	w.published.Store(int64(w.nodes))
+}
+
+// totalNodes returns the nodes searched by the worker and those published by the others.
+func (w *worker) totalNodes() int {
+	nodes := w.nodes
+	for _, other := range w.workers {
+		if other != w {
+			nodes += int(other.published.Load())
+		}
+	}
+	return nodes
+}
+
+// checkStop reports if the search must stop, checking the context every few nodes.
+// The main thread enforces the time limits for all, once it completed an iteration.
+// Every thread enforces the node limit when it publishes its nodes, the main thread
+// at each node so that a single thread stops right at the limit.
+func (w *worker) checkStop() bool {
+	if w.stopped {
+		return true
+	}
+	if w.nodes%publishInterval == 0 {
+		w.published.Store(int64(w.nodes))
+		if w.limits.Nodes > 0 && w.totalNodes() >= w.limits.Nodes {
+			w.stop.Store(true)
+		}
+	}
+	if w.nodes%checkInterval == 0 {
+		if w.ctx.Err() != nil || w.id == 0 && w.result.Depth > 0 && w.tm.hardStop() {
+			w.stop.Store(true)
+		}
+	}
+	if w.id == 0 && w.limits.Nodes > 0 && w.totalNodes() >= w.limits.Nodes {
+		w.stop.Store(true)
+	}
+	w.stopped = w.stop.Load()
+	return w.stopped
+}
+
+// negamax returns the score of the position for the side to move, searching the
+// principal variation of the previous iteration first, then the move of the transposition table.
+// Moves after the first are searched with a null window, again with the full one when they
+// turn out better.
+func (w *worker) negamax(p *position.Position, depth, ply int, alpha, beta Score, prevPV []move.Move) Score {
+	// Search checks deeper, before the captures are settled at the leaves
+	inCheck := p.InCheck()
+	if inCheck && w.Options.CheckExtensions && ply < MaxPly {
+		depth++
+	}
+	if depth <= 0 || ply >= MaxPly {
+		return w.quiescence(p, ply, alpha, beta)
+	}
+
+	w.pvLen[ply] = 0
+	w.nodes++
+	if w.checkStop() || isDraw(p, ply) {
+		return Score_Draw
+	}
+
+	// Use what a search of the position as deep already found
+	entry, found := w.TT.Probe(p.Hash)
+	if found && ply > 0 && entry.Depth >= depth {
+		score := scoreFromTT(entry.Score, ply)
+		switch {
+		case entry.Bound == Bound_Exact,
+			entry.Bound == Bound_Lower && score >= beta,
+			entry.Bound == Bound_Upper && score <= alpha:
+			return score
+		}
+	}
+
+	// Prune the positions far enough from the principal variation
+	isPV := beta-alpha > 1
+	var prev *move.Move
+	if ply > 0 {
+		prev = w.moveStack[ply-1]
+	}
+	staticEval := -Score_Infinite
+	if !isPV && !inCheck {
+		staticEval = w.evaluate(p)
+
+		// Reverse futility pruning: a margin by depth won't be lost
+		if w.Options.ReverseFutility && depth <= reverseFutilityMaxDepth && !beta.IsMate() &&
+			staticEval-Score(reverseFutilityMargin*depth) >= beta {
+			return staticEval
+		}
+
+		// Null move pruning: passing the turn is still too good, except in zugzwang or after a pass
+		if w.Options.NullMove && depth >= nullMoveMinDepth && staticEval >= beta && prev != nil &&
+			hasNonPawnMaterial(p, p.WhitesTurn) {
+			r := 3 + depth/6
+			w.moveStack[ply] = nil
+			p.MakeNull()
+			score := -w.negamax(p, depth-1-r, ply+1, -beta, -beta+1, nil)
+			p.Unmake()
+			if w.stopped {
+				return Score_Draw
+			}
+			if score >= beta {
+				// Don't trust unproven mates
+				return min(score, Score_MateBound-1)
+			}
+		}
+	}
+
+	// Search the previous principal variation first, or else the move of the table
+	hashMove := entry.Move
+	if len(prevPV) > 0 {
+		hashMove = prevPV[0].Compact()
+	}
+	mp := newMovePicker(p, &w.heuristics, hashMove, ply, prev, false)
+
+	origAlpha := alpha
+	best, bestMove := -Score_Infinite, move.Compact_None
+	var quietsTried []*move.Move
+	moves := 0
+	for m := mp.next(); m != nil; m = mp.next() {
+		moves++
+		quiet := isQuiet(m)
+		isRefutation := mp.stage == stage_Refutations
+		history := mp.lastScore
+		var nextPV []move.Move
+		if len(prevPV) > 0 && *m == prevPV[0] {
+			nextPV = prevPV[1:]
+		}
+
+		w.moveStack[ply] = m
+		p.Make(m.From, m.To, m.PromotedTo)
+		givesCheck := p.InCheck()
+
+		// Futility pruning: quiet moves can't raise a hopeless static evaluation
+		if w.Options.Futility && !isPV && !inCheck && !givesCheck && quiet && moves > 1 &&
+			depth <= futilityMaxDepth && staticEval+Score(futilityMargin*depth) <= alpha {
+			p.Unmake()
+			continue
+		}
+
+		var score Score
+		if moves == 1 {
+			score = -w.negamax(p, depth-1, ply+1, -beta, -alpha, nextPV)
+		} else {
+			// Late move reductions: late quiet moves are unlikely to be best
+			r := 0
+			if w.Options.LateMoveReductions && depth >= lmrMinDepth && moves >= lmrMinMoves && quiet &&
+				!inCheck && !givesCheck && !isRefutation {
+				r = lmrReduction(depth, moves, history, isPV)
+			}
+			score = -w.negamax(p, depth-1-r, ply+1, -alpha-1, -alpha, nextPV)
+			if score > alpha && r > 0 {
+				score = -w.negamax(p, depth-1, ply+1, -alpha-1, -alpha, nextPV)
+			}
+			if score > alpha && score < beta {
+				score = -w.negamax(p, depth-1, ply+1, -beta, -alpha, nextPV)
+			}
+		}
+		p.Unmake()
+		if w.stopped {
+			return Score_Draw
+		}
+
+		if score > best {
+			best, bestMove = score, m.Compact()
+		}
+		if score > alpha {
+			alpha = score
+
+			// Update the principal variation
+			w.pv[ply][0] = *m
+			copy(w.pv[ply][1:], w.pv[ply+1][:w.pvLen[ply+1]])
+			w.pvLen[ply] = w.pvLen[ply+1] + 1
+		}
+		if alpha >= beta {
+			if quiet {
+				w.heuristics.update(m, quietsTried, prev, ply, depth)
+			}
+			break
+		}
+		if quiet {
+			quietsTried = append(quietsTried, m)
+		}
+	}
+
+	// Mate and stalemate
+	if best == -Score_Infinite {
+		if inCheck {
+			return MatedIn(ply)
+		}
+		return Score_Draw
+	}
+
+	// Save the result
+	bound := Bound_Exact
+	if best <= origAlpha {
+		// No move proved best
+		bound, bestMove = Bound_Upper, move.Compact_None
+	} else if best >= beta {
+		bound = Bound_Lower
+	}
+	w.TT.Store(p.Hash, bestMove, scoreToTT(best, ply), depth, bound)
+	return best
+}
+
+// quiescence returns the score of the position once no good capture is left, letting
+// the side to move stand pat on the static evaluation unless it is in check.
+func (w *worker) quiescence(p *position.Position, ply int, alpha, beta Score) Score {
+	w.pvLen[ply] = 0
+	w.nodes++
+	if w.checkStop() || isDraw(p, ply) {
+		return Score_Draw
+	}
+	if ply >= MaxPly {
+		return w.evaluate(p)
+	}
+
+	// Search every evasion when in check, or else the captures that don't lose material
+	inCheck := p.InCheck()
+	best := -Score_Infinite
+	if !inCheck {
+		best = w.evaluate(p)
+		if best >= beta {
+			return best
+		}
+		alpha = max(alpha, best)
+	}
+
+	mp := newMovePicker(p, &w.heuristics, move.Compact_None, ply, nil, !inCheck)
+	for m := mp.next(); m != nil; m = mp.next() {
+		p.Make(m.From, m.To, m.PromotedTo)
+		score := -w.quiescence(p, ply+1, -beta, -alpha)
+		p.Unmake()
+		if w.stopped {
+			return Score_Draw
+		}
+
+		best = max(best, score)
+		if score > alpha {
+			alpha = score
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+
+	if best == -Score_Infinite {
+		return MatedIn(ply)
+	}
+	return best
+}
+
+// isDraw reports if the position below the root is drawn by repetition, the fifty-move rule
+// or insufficient material. A single repetition is enough, playing on can't do better.
+func isDraw(p *position.Position, ply int) bool {
+	return ply > 0 && (p.Repetitions() > 1 || p.HalfmoveCount >= 100 || p.HasInsufficientMaterial())
+}
+
+// evaluate returns the static evaluation for the side to move.
+func (w *worker) evaluate(p *position.Position) Score {
+	score := Score(w.evaluator.Evaluate(p))
+	if !p.WhitesTurn {
+		return -score
+	}
+	return score
+}
@@ -0,0 +1,204 @@
+package search_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/position"
+	"gochess/pkg/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		score    search.Score
+		isMate   bool
+		expected string
+	}{
+		{0, false, "cp 0"},
+		{-35, false, "cp -35"},
+		{search.MateIn(1), true, "mate 1"},
+		{search.MateIn(3), true, "mate 2"},
+		{search.MatedIn(2), true, "mate -1"},
+		{search.MatedIn(4), true, "mate -2"},
+	}
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			assert.Equal(t, test.isMate, test.score.IsMate())
+			assert.Equal(t, test.expected, test.score.String())
+		})
+	}
+}
+
+// searchTests are positions with a known best move, found whatever the options and threads.
+var searchTests = []struct {
+	name          string
+	fen           position.FEN
+	depth         int
+	expectedMove  move.PCN
+	expectedScore search.Score
+}{
+	{"Mate In One", "6k1/5ppp/8/8/8/8/8/R3K3 w - - 0 1", 3, "a1a8", search.MateIn(1)},
+	{"Mate In Two", "kbK5/pp6/1P6/8/8/8/8/R7 w - - 0 1", 4, "a1a6", search.MateIn(3)},
+	{"Mate In Three", "r5rk/5p1p/5R2/4B3/8/8/7P/7K w - - 0 1", 6, "f6a6", search.MateIn(5)},
+	{"Mated In One", "7k/8/6K1/8/8/8/8/R7 b - - 0 1", 4, "h8g8", search.MatedIn(2)},
+	{"Free Queen", "4k3/8/8/3q4/8/8/8/3RK3 w - - 0 1", 2, "d1d5", 500},
+	{"Defended Pawn", "4k3/8/4p3/3p4/8/8/8/3QK3 w - - 0 1", 1, "", 800},
+	{"Free Bishop", "4k3/8/2n5/3b4/8/8/6B1/4K3 w - - 0 1", 1, "g2d5", 0},
+}
+
+func TestSearch(t *testing.T) {
+	configs := []struct {
+		name    string
+		options search.Options
+		threads int
+	}{
+		{"Default", search.DefaultOptions, 1},
+		{"No Selectivity", search.Options{}, 1},
+		{"No Null Move", search.Options{LateMoveReductions: true, ReverseFutility: true, Futility: true, CheckExtensions: true}, 1},
+		{"Only Null Move", search.Options{NullMove: true}, 1},
+		{"Only Reductions", search.Options{LateMoveReductions: true}, 1},
+		{"Only Pruning", search.Options{ReverseFutility: true, Futility: true}, 1},
+		{"Only Extensions", search.Options{CheckExtensions: true}, 1},
+		{"Threads", search.DefaultOptions, 4},
+	}
+	for _, test := range searchTests {
+		for _, c := range configs {
+			t.Run(test.name+"/"+c.name, func(t *testing.T) {
+				p, err := position.NewPosition(test.fen)
+				require.NoError(t, err)
+
+				s := search.NewSearcher()
+				s.Options, s.Threads = c.options, c.threads
+				result := s.Search(context.Background(), p, search.Limits{Depth: test.depth})
+				require.NotNil(t, result.Move)
+				if test.expectedMove != "" {
+					assert.Equal(t, test.expectedMove, result.Move.PCN())
+				}
+				if test.expectedScore.IsMate() {
+					assert.Equal(t, test.expectedScore, result.Score)
+				} else {
+					// Material with some positional difference
+					assert.InDelta(t, int(test.expectedScore), int(result.Score), 100)
+				}
+				assert.Equal(t, *result.Move, result.PV[0])
+				assert.Equal(t, test.fen, p.FEN())
+			})
+		}
+	}
+
+	// No legal move
+	p, err := position.NewPosition("7k/5Q2/6K1/8/8/8/8/8 b - - 0 1")
+	require.NoError(t, err)
+	result := search.NewSearcher().Search(context.Background(), p, search.Limits{Depth: 3})
+	assert.Nil(t, result.Move)
+}
+
+func TestSearchOptions(t *testing.T) {
+	// Selectivity searches fewer nodes
+	p, err := position.NewPosition("r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1")
+	require.NoError(t, err)
+	s := search.NewSearcher()
+	s.Options = search.Options{}
+	full := s.Search(context.Background(), p, search.Limits{Depth: 5})
+	selective := search.NewSearcher().Search(context.Background(), p, search.Limits{Depth: 5})
+	assert.Less(t, selective.Nodes, full.Nodes)
+}
+
+func TestSearchThreads(t *testing.T) {
+	// The threads stop together
+	p, err := position.NewPosition(position.StartingFEN)
+	require.NoError(t, err)
+	s := search.NewSearcher()
+	s.Threads = 4
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	result := s.Search(ctx, p, search.Limits{})
+	assert.Less(t, time.Since(start), time.Second)
+	require.NotNil(t, result.Move)
+	assert.Positive(t, result.Depth)
+
+	// The node limit counts the nodes of all threads, published every few hundred,
+	// not those of the previous search
+	result = s.Search(context.Background(), p, search.Limits{Nodes: 50000})
+	require.NotNil(t, result.Move)
+	assert.Positive(t, result.Depth)
+	assert.GreaterOrEqual(t, result.Nodes, 50000)
+	assert.Less(t, result.Nodes, 50000+4*256)
+
+	// Fewer threads reuse the same searcher
+	s.Threads = 1
+	result = s.Search(context.Background(), p, search.Limits{Depth: 3})
+	assert.Equal(t, 3, result.Depth)
+	assert.Equal(t, position.StartingFEN, p.FEN())
+}
+
+func TestSearchInfo(t *testing.T) {
+	p, err := position.NewPosition(position.StartingFEN)
+	require.NoError(t, err)
+
+	var infos []search.Info
+	s := search.NewSearcher()
+	s.OnInfo = func(info search.Info) { infos = append(infos, info) }
+	result := s.Search(context.Background(), p, search.Limits{Depth: 3})
+	require.Len(t, infos, 3)
+	for i, info := range infos {
+		assert.Equal(t, i+1, info.Depth)
+		assert.Len(t, info.PV, i+1)
+	}
+	assert.Equal(t, 3, result.Depth)
+	assert.Equal(t, infos[2].PV, result.PV)
+	assert.Greater(t, result.Nodes, infos[1].Nodes)
+}
+
+func TestSearchStop(t *testing.T) {
+	p, err := position.NewPosition(position.StartingFEN)
+	require.NoError(t, err)
+
+	// Cancelled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result := search.NewSearcher().Search(ctx, p, search.Limits{})
+	require.NotNil(t, result.Move)
+	assert.Equal(t, position.StartingFEN, p.FEN())
+
+	// Deadline
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	result = search.NewSearcher().Search(ctx, p, search.Limits{})
+	assert.Less(t, time.Since(start), time.Second)
+	require.NotNil(t, result.Move)
+	assert.Positive(t, result.Depth)
+
+	// Node limit
+	result = search.NewSearcher().Search(context.Background(), p, search.Limits{Nodes: 5000})
+	assert.LessOrEqual(t, result.Nodes, 5000)
+	require.NotNil(t, result.Move)
+
+	// Move time
+	start = time.Now()
+	result = search.NewSearcher().Search(context.Background(), p, search.Limits{MoveTime: 100 * time.Millisecond})
+	assert.Less(t, time.Since(start), time.Second)
+	require.NotNil(t, result.Move)
+	assert.Positive(t, result.Depth)
+
+	// Clock, a single legal move is played at once
+	start = time.Now()
+	result = search.NewSearcher().Search(context.Background(), p, search.Limits{Time: time.Second, Inc: 100 * time.Millisecond})
+	assert.Less(t, time.Since(start), time.Second/2)
+	require.NotNil(t, result.Move)
+	p, err = position.NewPosition("k7/8/8/8/8/8/1q6/K7 w - - 0 1")
+	require.NoError(t, err)
+	start = time.Now()
+	result = search.NewSearcher().Search(context.Background(), p, search.Limits{Time: time.Minute})
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+	require.NotNil(t, result.Move)
+	assert.Equal(t, move.PCN("a1b2"), result.Move.PCN())
+	assert.Equal(t, 1, result.Depth)
+}
@@ -0,0 +1,103 @@
+package search
+
+import (
+	"time"
+
+	"gochess/pkg/notation/move"
+)
+
+const (
+	DefaultMoveOverhead = 30 * time.Millisecond
+	MaxMoveOverhead     = 5 * time.Second
+
+	defaultMovesToGo = 30 // Moves left in sudden death, as far as time is concerned
+	hardLimitFactor  = 4  // How much longer than planned an unstable search can run
+	minThinkTime     = time.Millisecond
+	scoreDropMargin  = 20  // Score lost since the previous iteration that calls for more time
+	maxScoreDrop     = 200 // Score lost that calls for the most time
+)
+
+// timeManager decides when the main thread stops searching from the clock. It plans a soft
+// limit checked between iterations, stretched when the best move changes or the score drops,
+// and a hard limit checked during them.
+type timeManager struct {
+	limited   bool            // Whether the clock limits the search
+	start     time.Time       // When the clock started, at ponderhit when pondering
+	ponderHit <-chan struct{} // Nil once the clock runs
+	soft      time.Duration
+	hard      time.Duration
+
+	// Previous iteration
+	bestMove  move.Move
+	bestScore Score
+	changes   float64 // Best move changes, the older ones counting less
+}
+
+// init plans the time of the move, stopping after the first iteration when there is a single legal move.
+func (tm *timeManager) init(limits Limits, overhead time.Duration, legalMoves int) {
+	*tm = timeManager{start: time.Now(), ponderHit: limits.PonderHit}
+	switch {
+	case limits.MoveTime > 0:
+		tm.limited = true
+		tm.soft = max(limits.MoveTime-overhead, minThinkTime)
+		tm.hard = tm.soft
+	case limits.Time > 0:
+		tm.limited = true
+		movesToGo := limits.MovesToGo
+		if movesToGo <= 0 {
+			movesToGo = defaultMovesToGo
+		}
+
+		// Share the time left and the increments to come between the moves to go,
+		// never planning to use more than half of the clock or the hard limit more than most of it
+		maxTime := max(limits.Time-overhead, minThinkTime)
+		available := limits.Time + limits.Inc*time.Duration(movesToGo-1) - overhead*time.Duration(movesToGo)
+		tm.soft = max(min(available/time.Duration(movesToGo), maxTime/2), minThinkTime)
+		tm.hard = max(min(tm.soft*hardLimitFactor, maxTime*3/4), tm.soft)
+	}
+	if tm.limited && legalMoves == 1 {
+		tm.soft, tm.hard = 0, 0
+	}
+}
+
+// running reports if the clock limits the search, starting it at ponderhit.
+func (tm *timeManager) running() bool {
+	if tm.ponderHit != nil {
+		select {
+		case <-tm.ponderHit:
+			tm.ponderHit, tm.start = nil, time.Now()
+		default:
+			return false
+		}
+	}
+	return tm.limited
+}
+
+// hardStop reports if the search must stop right away.
+func (tm *timeManager) hardStop() bool {
+	return tm.running() && time.Since(tm.start) >= tm.hard
+}
+
+// softStop reports if the search should stop after the iteration, taking more time when
+// the best move keeps changing or the score drops.
+func (tm *timeManager) softStop(result Result) bool {
+	// Compare with the previous iteration
+	scale := 1.0
+	if tm.bestMove != (move.Move{}) {
+		tm.changes /= 2
+		if *result.Move != tm.bestMove {
+			tm.changes++
+		}
+		scale += tm.changes
+		if drop := tm.bestScore - result.Score; drop > scoreDropMargin && !result.Score.IsMate() && !tm.bestScore.IsMate() {
+			scale *= 1 + float64(min(drop, maxScoreDrop))/maxScoreDrop/2
+		}
+	}
+	tm.bestMove, tm.bestScore = *result.Move, result.Score
+
+	if !tm.running() {
+		return false
+	}
+	soft := min(time.Duration(float64(tm.soft)*scale), tm.hard)
+	return time.Since(tm.start) >= soft
+}
@@ -0,0 +1,81 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/square"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeManagerLimits(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name       string
+		limits     Limits
+		legalMoves int
+		limited    bool
+		soft, hard time.Duration
+	}{
+		{"No Clock", Limits{Depth: 5}, 20, false, 0, 0},
+		{"Move Time", Limits{MoveTime: 1000 * ms}, 20, true, 970 * ms, 970 * ms},
+		{"Sudden Death", Limits{Time: 60000 * ms}, 20, true, 1970 * ms, 7880 * ms},
+		{"Increment", Limits{Time: 60000 * ms, Inc: 1000 * ms}, 20, true, 2936 * ms, 11746 * ms},
+		{"Moves To Go", Limits{Time: 10000 * ms, MovesToGo: 1}, 20, true, 4985 * ms, 7477*ms + 500*time.Microsecond},
+		{"Short Of Time", Limits{Time: 20 * ms}, 20, true, ms, ms},
+		{"Single Move", Limits{Time: 60000 * ms}, 1, true, 0, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var tm timeManager
+			tm.init(test.limits, DefaultMoveOverhead, test.legalMoves)
+			assert.Equal(t, test.limited, tm.limited)
+			assert.InDelta(t, test.soft, tm.soft, float64(ms))
+			assert.InDelta(t, test.hard, tm.hard, float64(ms))
+			assert.LessOrEqual(t, tm.soft, tm.hard)
+		})
+	}
+}
+
+func TestTimeManagerSoftStop(t *testing.T) {
+	e2e4 := move.Move{From: square.Square_e2, To: square.Square_e4}
+	d2d4 := move.Move{From: square.Square_d2, To: square.Square_d4}
+
+	// Past the planned time with a stable best move
+	var tm timeManager
+	tm.init(Limits{Time: 60 * time.Second}, 0, 20)
+	tm.start = time.Now().Add(-tm.soft * 3 / 2)
+	assert.True(t, tm.softStop(Result{Move: &e2e4, Score: 30}))
+
+	// The best move changing takes more time, until it is stable again
+	tm.init(Limits{Time: 60 * time.Second}, 0, 20)
+	tm.start = time.Now().Add(-tm.soft * 3 / 2)
+	tm.softStop(Result{Move: &e2e4, Score: 30})
+	assert.False(t, tm.softStop(Result{Move: &d2d4, Score: 30}))
+	assert.False(t, tm.softStop(Result{Move: &e2e4, Score: 30}))
+	assert.False(t, tm.softStop(Result{Move: &e2e4, Score: 30}))
+	assert.True(t, tm.softStop(Result{Move: &e2e4, Score: 30}))
+
+	// So does the score dropping
+	tm.init(Limits{Time: 60 * time.Second}, 0, 20)
+	tm.start = time.Now().Add(-tm.soft * 6 / 5)
+	tm.softStop(Result{Move: &e2e4, Score: 30})
+	assert.False(t, tm.softStop(Result{Move: &e2e4, Score: -100}))
+	assert.True(t, tm.softStop(Result{Move: &e2e4, Score: -100}))
+
+	// Never past the hard limit
+	tm.init(Limits{Time: 60 * time.Second}, 0, 20)
+	tm.start = time.Now().Add(-tm.hard)
+	tm.softStop(Result{Move: &e2e4, Score: 30})
+	assert.True(t, tm.softStop(Result{Move: &d2d4, Score: -200}))
+
+	// The clock waits for ponderhit
+	ponderHit := make(chan struct{})
+	tm.init(Limits{Time: 60 * time.Second, PonderHit: ponderHit}, 0, 1)
+	assert.False(t, tm.hardStop())
+	assert.False(t, tm.softStop(Result{Move: &e2e4}))
+	close(ponderHit)
+	assert.True(t, tm.hardStop())
+}
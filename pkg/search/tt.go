@@ -0,0 +1,199 @@
+package search
+
+import (
+	"math/bits"
+	"sync/atomic"
+	"unsafe"
+
+	"gochess/pkg/notation/move"
+)
+
+const (
+	DefaultHashMB = 16
+	MaxHashMB     = 4096
+
+	bucketSize = 4
+	ageMask    = 0x3F
+)
+
+// ==================== Bound ====================
+
+// Bound tells how the score of an entry relates to the real score of the position.
+type Bound uint8
+
+const (
+	Bound_None  Bound = iota
+	Bound_Exact       // The score is exact
+	Bound_Lower       // The score failed high, the real one is at least as good
+	Bound_Upper       // The score failed low, the real one is at most as good
+)
+
+// ==================== Entry ====================
+
+// TTEntry is what the transposition table knows about a position.
+type TTEntry struct {
+	Move  move.Compact
+	Score Score
+	Depth int
+	Bound Bound
+	age   uint8
+}
+
+// ttEntry packs an entry in the data word, bits 0-15 move, 16-31 score, 32-39 depth,
+// 40-41 bound and 42-47 age. An empty entry has no bound. Threads share the entries
+// without locks: the key is stored xor-ed with the data, so an entry torn by two threads
+// writing it at once doesn't match its key anymore.
+type ttEntry struct {
+	key  atomic.Uint64
+	data atomic.Uint64
+}
+
+// load returns the key and data of the entry, a zero key when it is empty.
+func (e *ttEntry) load() (uint64, uint64) {
+	data := e.data.Load()
+	if data == 0 {
+		return 0, 0
+	}
+	return e.key.Load() ^ data, data
+}
+
+func packEntry(e TTEntry) uint64 {
+	return uint64(e.Move) | uint64(uint16(e.Score))<<16 | uint64(uint8(e.Depth))<<32 |
+		uint64(e.Bound)<<40 | uint64(e.age&ageMask)<<42
+}
+
+func unpackEntry(data uint64) TTEntry {
+	return TTEntry{
+		Move:  move.Compact(data),
+		Score: Score(int16(data >> 16)),
+		Depth: int(uint8(data >> 32)),
+		Bound: Bound(data >> 40 & 0x3),
+		age:   uint8(data >> 42 & ageMask),
+	}
+}
+
+type ttBucket [bucketSize]ttEntry
+
+// ==================== Transposition Table ====================
+
+// TranspositionTable remembers searched positions by hash in buckets of entries,
+// replacing the shallowest and oldest entries first. Probe and Store are safe for
+// concurrent use, the other methods must be called between searches.
+type TranspositionTable struct {
+	buckets []ttBucket
+	age     uint8
+}
+
+func NewTranspositionTable(sizeMB int) *TranspositionTable {
+	t := &TranspositionTable{}
+	t.Resize(sizeMB)
+	return t
+}
+
+// Resize clears the table and gives it the size in megabytes.
+func (t *TranspositionTable) Resize(sizeMB int) {
+	sizeMB = min(max(sizeMB, 1), MaxHashMB)
+	t.buckets = make([]ttBucket, sizeMB<<20/int(unsafe.Sizeof(ttBucket{})))
+	t.age = 0
+}
+
+// Clear empties the table.
+func (t *TranspositionTable) Clear() {
+	clear(t.buckets)
+	t.age = 0
+}
+
+// NewSearch ages the entries, so those of previous searches are replaced first.
+func (t *TranspositionTable) NewSearch() {
+	t.age = (t.age + 1) & ageMask
+}
+
+func (t *TranspositionTable) bucket(key uint64) *ttBucket {
+	i, _ := bits.Mul64(key, uint64(len(t.buckets)))
+	return &t.buckets[i]
+}
+
+// Probe returns the entry of the position with the hash, if any.
+func (t *TranspositionTable) Probe(key uint64) (TTEntry, bool) {
+	b := t.bucket(key)
+	for i := range b {
+		if k, data := b[i].load(); k == key && data != 0 {
+			return unpackEntry(data), true
+		}
+	}
+	return TTEntry{}, false
+}
+
+// Store saves what the search found about the position with the hash.
+func (t *TranspositionTable) Store(key uint64, m move.Compact, score Score, depth int, bound Bound) {
+	b := t.bucket(key)
+
+	// Pick the entry of the position, or else an empty one, or else the least valuable
+	victim := 0
+	victimValue := int(^uint(0) >> 1)
+	var victimKey, victimData uint64
+	for i := range b {
+		k, data := b[i].load()
+		if k == key || data == 0 {
+			victim, victimKey, victimData = i, k, data
+			break
+		}
+		e := unpackEntry(data)
+		if value := e.Depth - 8*int((t.age-e.age)&ageMask); value < victimValue {
+			victim, victimValue, victimKey, victimData = i, value, k, data
+		}
+	}
+
+	// Keep a deeper result of the same search, and the move when there is no new one
+	if victimKey == key && victimData != 0 {
+		old := unpackEntry(victimData)
+		if old.age == t.age && old.Depth > depth && bound != Bound_Exact {
+			return
+		}
+		if m == move.Compact_None {
+			m = old.Move
+		}
+	}
+
+	data := packEntry(TTEntry{Move: m, Score: score, Depth: depth, Bound: bound, age: t.age})
+	b[victim].key.Store(key ^ data)
+	b[victim].data.Store(data)
+}
+
+// Hashfull returns the permill of entries used by the current search, sampling the first ones.
+func (t *TranspositionTable) Hashfull() int {
+	var used, total int
+	for i := 0; i < len(t.buckets) && total < 1000; i++ {
+		for j := range t.buckets[i] {
+			if data := t.buckets[i][j].data.Load(); data != 0 && unpackEntry(data).age == t.age {
+				used++
+			}
+			total++
+		}
+	}
+	return used * 1000 / total
+}
+
+// scoreToTT makes mate scores relative to the position rather than the root.
+func scoreToTT(s Score, ply int) Score {
+	switch {
+	case s >= Score_MateBound:
+		return s + Score(ply)
+	case s <= -Score_MateBound:
+		return s - Score(ply)
+	default:
+		return s
+	}
+}
+
+// scoreFromTT makes mate scores from the table relative to the root.
+func scoreFromTT(s Score, ply int) Score {
+	switch {
+	case s >= Score_MateBound:
+		return s - Score(ply)
+	case s <= -Score_MateBound:
+		return s + Score(ply)
+	default:
+		return s
+	}
+}
@@ -0,0 +1,115 @@
+package search_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/piece"
+	"gochess/pkg/notation/position"
+	"gochess/pkg/notation/square"
+	"gochess/pkg/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompactMove(t *testing.T) {
+	m := move.Move{From: square.Square_b2, To: square.Square_a1, Piece: piece.Piece_BlackPawn,
+		PromotedTo: piece.Piece_BlackKnight, IsCapture: true}
+	c := m.Compact()
+	assert.Equal(t, square.Square_b2, c.From())
+	assert.Equal(t, square.Square_a1, c.To())
+	assert.Equal(t, piece.Piece_BlackKnight, c.PromotedTo(false))
+	assert.True(t, m.Matches(c))
+	assert.False(t, move.Move{From: square.Square_b2, To: square.Square_a1, PromotedTo: piece.Piece_BlackQueen}.Matches(c))
+	assert.False(t, move.Move{}.Matches(move.Compact_None))
+}
+
+func TestTranspositionTable(t *testing.T) {
+	tt := search.NewTranspositionTable(1)
+	m := move.Move{From: square.Square_e2, To: square.Square_e4}.Compact()
+
+	// Store and probe
+	_, found := tt.Probe(42)
+	assert.False(t, found)
+	tt.Store(42, m, -150, 6, search.Bound_Lower)
+	entry, found := tt.Probe(42)
+	require.True(t, found)
+	assert.Equal(t, m, entry.Move)
+	assert.Equal(t, search.Score(-150), entry.Score)
+	assert.Equal(t, 6, entry.Depth)
+	assert.Equal(t, search.Bound_Lower, entry.Bound)
+
+	// A shallower bound of the same search doesn't replace a deeper one
+	tt.Store(42, move.Compact_None, 20, 3, search.Bound_Upper)
+	entry, _ = tt.Probe(42)
+	assert.Equal(t, 6, entry.Depth)
+
+	// A new search replaces it, keeping the move
+	tt.NewSearch()
+	tt.Store(42, move.Compact_None, 20, 3, search.Bound_Upper)
+	entry, _ = tt.Probe(42)
+	assert.Equal(t, 3, entry.Depth)
+	assert.Equal(t, m, entry.Move)
+
+	// Small keys share the first bucket, once full the shallowest entry is replaced
+	for i := uint64(1); i <= 4; i++ {
+		tt.Store(42+i, m, 0, int(i), search.Bound_Exact)
+	}
+	for i, expected := range []bool{true, false, true, true, true} {
+		_, found = tt.Probe(42 + uint64(i))
+		assert.Equal(t, expected, found, 42+i)
+	}
+
+	// Entries of older searches are replaced first
+	tt.NewSearch()
+	tt.Store(50, m, 0, 1, search.Bound_Exact)
+	tt.NewSearch()
+	tt.Store(51, m, 0, 1, search.Bound_Exact)
+	for key, expected := range map[uint64]bool{42: false, 44: false, 45: true, 46: true, 50: true, 51: true} {
+		_, found = tt.Probe(key)
+		assert.Equal(t, expected, found, key)
+	}
+
+	tt.Clear()
+	_, found = tt.Probe(43)
+	assert.False(t, found)
+	assert.Equal(t, 0, tt.Hashfull())
+}
+
+func TestTranspositionTableConcurrent(t *testing.T) {
+	tt := search.NewTranspositionTable(1)
+
+	// Threads writing the same keys never read an entry of another key
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10000; j++ {
+				key := uint64(j % 64)
+				tt.Store(key, move.Compact(key), search.Score(key), i+1, search.Bound_Exact)
+				if entry, found := tt.Probe(key); found {
+					assert.Equal(t, move.Compact(key), entry.Move)
+					assert.Equal(t, search.Score(key), entry.Score)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestSearchHashfull(t *testing.T) {
+	p, err := position.NewPosition(position.StartingFEN)
+	require.NoError(t, err)
+
+	var hashfull int
+	s := search.NewSearcher()
+	s.TT.Resize(1)
+	s.OnInfo = func(info search.Info) { hashfull = info.Hashfull }
+	s.Search(context.Background(), p, search.Limits{Depth: 4})
+	assert.Positive(t, hashfull)
+	assert.LessOrEqual(t, hashfull, 1000)
+}
@@ -0,0 +1,90 @@
+package tuning
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gochess/pkg/notation/position"
+)
+
+// Entry is a quiet position labelled with the result of its game.
+type Entry struct {
+	Position *position.Position
+	Result   float64 // 1 when white won, 0.5 for a draw and 0 when black won
+}
+
+// ReadEntries reads one labelled position per line, the FEN followed by the result as
+// 1-0, 1/2-1/2 or 0-1 or as the score of white 1.0, 0.5 or 0.0, bare or in brackets or quotes:
+//
+//	rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1 [0.5]
+//	rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - c9 "1/2-1/2";
+//
+// The clocks of the FEN are optional. Empty lines and lines starting with # are skipped.
+func ReadEntries(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		entry, err := parseEntry(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func parseEntry(text string) (Entry, error) {
+	fields := strings.Fields(text)
+	if len(fields) < 5 {
+		return Entry{}, fmt.Errorf("missing fen or result in %q", text)
+	}
+
+	// Find the result, the last field that is one
+	result, found := 0.0, false
+	for i := len(fields) - 1; i >= 4 && !found; i-- {
+		result, found = parseResult(fields[i])
+	}
+	if !found {
+		return Entry{}, fmt.Errorf("missing result in %q", text)
+	}
+
+	// Fill in the clocks when missing
+	fen := strings.Join(fields[:4], " ")
+	if len(fields) >= 6 && isCount(fields[4]) && isCount(fields[5]) {
+		fen += " " + fields[4] + " " + fields[5]
+	} else {
+		fen += " 0 1"
+	}
+	p, err := position.NewPosition(position.FEN(fen))
+	if err != nil {
+		return Entry{}, fmt.Errorf("invalid fen %q: %w", fen, err)
+	}
+	return Entry{Position: p, Result: result}, nil
+}
+
+func parseResult(field string) (float64, bool) {
+	switch strings.Trim(field, `[]";`) {
+	case "1-0", "1.0":
+		return 1, true
+	case "1/2-1/2", "0.5":
+		return 0.5, true
+	case "0-1", "0.0":
+		return 0, true
+	}
+	return 0, false
+}
+
+func isCount(field string) bool {
+	_, err := strconv.Atoi(field)
+	return err == nil
+}
@@ -0,0 +1,135 @@
+package tuning
+
+import (
+	"math"
+	"runtime"
+	"sync"
+
+	"gochess/pkg/evaluation"
+)
+
+// Tuner fits the evaluation parameters to the results of the games of labelled positions,
+// minimizing the squared error between the results and the evaluations mapped to
+// expected scores by a sigmoid, with the local search of the Texel tuning method.
+type Tuner struct {
+	Entries []Entry
+	Params  evaluation.Params
+	K       float64 // Scales the evaluation in the sigmoid
+	Threads int
+
+	// OnPass is called after each pass over the weights with the error reached
+	OnPass func(pass int, err float64)
+}
+
+func NewTuner(entries []Entry, params evaluation.Params) *Tuner {
+	return &Tuner{Entries: entries, Params: params, K: 1, Threads: runtime.NumCPU()}
+}
+
+// sigmoid maps a score in centipawns to the expected score of white.
+func sigmoid(k float64, score int) float64 {
+	return 1 / (1 + math.Pow(10, -k*float64(score)/400))
+}
+
+// Error returns the mean squared error of the current parameters.
+func (t *Tuner) Error() float64 {
+	return t.errorWith(t.K)
+}
+
+func (t *Tuner) errorWith(k float64) float64 {
+	if len(t.Entries) == 0 {
+		return 0
+	}
+
+	// Split the entries between the threads
+	threads := max(t.Threads, 1)
+	sums := make([]float64, threads)
+	var wg sync.WaitGroup
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			// Without a pawn table, the structures change with the parameters
+			e := &evaluation.Evaluator{Params: &t.Params, Terms: evaluation.Term_All}
+			for j := i; j < len(t.Entries); j += threads {
+				d := t.Entries[j].Result - sigmoid(k, e.Evaluate(t.Entries[j].Position))
+				sums[i] += d * d
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var sum float64
+	for _, s := range sums {
+		sum += s
+	}
+	return sum / float64(len(t.Entries))
+}
+
+// FindK sets K to the scale that best fits the current parameters, with a golden-section search.
+func (t *Tuner) FindK() {
+	lo, hi := 0.0, 10.0
+	ratio := (math.Sqrt(5) - 1) / 2
+	k1, k2 := hi-ratio*(hi-lo), lo+ratio*(hi-lo)
+	e1, e2 := t.errorWith(k1), t.errorWith(k2)
+	for hi-lo > 1e-4 {
+		if e1 < e2 {
+			hi, k2, e2 = k2, k1, e1
+			k1 = hi - ratio*(hi-lo)
+			e1 = t.errorWith(k1)
+		} else {
+			lo, k1, e1 = k1, k2, e2
+			k2 = lo + ratio*(hi-lo)
+			e2 = t.errorWith(k2)
+		}
+	}
+	t.K = (lo + hi) / 2
+}
+
+// Tune nudges each weight up or down by one while it lowers the error, in passes over
+// all the weights until none improves or the number of passes is reached, zero meaning
+// no limit. Weights that change nothing in the first pass are left alone afterwards.
+// It returns the error reached.
+func (t *Tuner) Tune(maxPasses int) float64 {
+	weights := t.Params.Weights()
+	active := make([]bool, len(weights))
+	for i := range active {
+		active[i] = true
+	}
+
+	best := t.Error()
+	for pass := 1; maxPasses <= 0 || pass <= maxPasses; pass++ {
+		improved := false
+		for i, w := range weights {
+			if !active[i] {
+				continue
+			}
+
+			// Try both directions
+			changed := false
+			for _, step := range []int{1, -1} {
+				*w += step
+				err := t.Error()
+				if err < best {
+					best, improved, changed = err, true, true
+					break
+				}
+				*w -= step
+				if err != best {
+					changed = true
+				}
+			}
+			if pass == 1 && !changed {
+				active[i] = false
+			}
+		}
+
+		if t.OnPass != nil {
+			t.OnPass(pass, best)
+		}
+		if !improved {
+			break
+		}
+	}
+	return best
+}
@@ -0,0 +1,76 @@
+package tuning_test
+
+import (
+	"strings"
+	"testing"
+
+	"gochess/pkg/evaluation"
+	"gochess/pkg/notation/piece"
+	"gochess/pkg/tuning"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadEntries(t *testing.T) {
+	entries, err := tuning.ReadEntries(strings.NewReader(strings.Join([]string{
+		"# Comment",
+		"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1 [0.5]",
+		"",
+		`4k3/8/8/8/8/8/8/3QK3 w - - c9 "1-0";`,
+		"4k3/8/8/8/8/8/8/3qK3 w - - 3 40 0-1",
+		"4k3/8/8/8/8/8/8/3RK3 b - - [1.0]",
+	}, "\n")))
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	assert.Equal(t, []float64{0.5, 1, 0, 1}, []float64{entries[0].Result, entries[1].Result, entries[2].Result, entries[3].Result})
+	assert.Equal(t, "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1", string(entries[0].Position.FEN()))
+	assert.Equal(t, 40, entries[2].Position.FullmoveCount)
+
+	// Errors
+	tests := []struct {
+		line     string
+		expected string
+	}{
+		{"4k3/8/8/8/8/8/8/3QK3 w - -", `line 1: missing fen or result in "4k3/8/8/8/8/8/8/3QK3 w - -"`},
+		{"4k3/8/8/8/8/8/8/3QK3 w - - 0 1 win", `line 1: missing result in "4k3/8/8/8/8/8/8/3QK3 w - - 0 1 win"`},
+		{"4k3/8/8/8/8/8/3QK3 w - - 1-0", `line 1: invalid fen "4k3/8/8/8/8/8/3QK3 w - - 0 1"`},
+	}
+	for _, test := range tests {
+		_, err := tuning.ReadEntries(strings.NewReader(test.line))
+		assert.ErrorContains(t, err, test.expected)
+	}
+}
+
+func TestTuner(t *testing.T) {
+	// White wins a rook up, draws a knight up and loses a knight down
+	entries, err := tuning.ReadEntries(strings.NewReader(strings.Join([]string{
+		"4k3/8/8/8/8/8/8/R3K3 w - - 1-0",
+		"4k3/8/8/8/8/8/8/R3K3 b - - 1-0",
+		"r3k3/8/8/8/8/8/8/4K3 w - - 0-1",
+		"4k3/8/8/8/8/8/8/1N2K3 w - - 1/2-1/2",
+		"4k3/8/8/8/8/8/8/1N2K3 b - - 1/2-1/2",
+		"1n2k3/8/8/8/8/8/8/4K3 w - - 1/2-1/2",
+	}, "\n")))
+	require.NoError(t, err)
+
+	tuner := tuning.NewTuner(entries, evaluation.DefaultParams)
+	tuner.FindK()
+	assert.Positive(t, tuner.K)
+	start := tuner.Error()
+
+	var passes []float64
+	tuner.OnPass = func(pass int, err float64) {
+		assert.Equal(t, len(passes)+1, pass)
+		passes = append(passes, err)
+	}
+	err2 := tuner.Tune(3)
+	assert.Less(t, err2, start)
+	assert.Len(t, passes, 3)
+	assert.Equal(t, err2, passes[2])
+	assert.Equal(t, err2, tuner.Error())
+
+	// Knights are worth less when they only draw
+	assert.Less(t, tuner.Params.PieceValues[piece.Piece_Knight].EG, evaluation.DefaultParams.PieceValues[piece.Piece_Knight].EG)
+	assert.Greater(t, tuner.Params.PieceValues[piece.Piece_Rook].EG, evaluation.DefaultParams.PieceValues[piece.Piece_Rook].EG)
+}
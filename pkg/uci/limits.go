@@ -0,0 +1,91 @@
+package uci
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"gochess/pkg/search"
+)
+
+// Limits are the conditions given by go to stop searching.
+type Limits struct {
+	Depth     int
+	Nodes     int
+	MoveTime  time.Duration
+	WhiteTime time.Duration
+	BlackTime time.Duration
+	WhiteInc  time.Duration
+	BlackInc  time.Duration
+	MovesToGo int
+	Infinite  bool
+	Ponder    bool // Searching on the opponent's time until ponderhit
+}
+
+// parseLimits handles the arguments of: go [depth <plies>] [movetime <ms>] [wtime <ms>] [btime <ms>] ...
+func parseLimits(args []string) (Limits, error) {
+	var l Limits
+	for i := 0; i < len(args); i++ {
+		// Flags without a value
+		switch args[i] {
+		case "infinite":
+			l.Infinite = true
+			continue
+		case "ponder":
+			l.Ponder = true
+			continue
+		case "searchmoves":
+			return l, fmt.Errorf("searchmoves isn't supported")
+		}
+
+		// Integer values
+		if i+1 >= len(args) {
+			return l, fmt.Errorf("missing value of %s", args[i])
+		}
+		v, err := strconv.Atoi(args[i+1])
+		if err != nil {
+			return l, fmt.Errorf("invalid value of %s: %w", args[i], err)
+		}
+		ms := time.Duration(v) * time.Millisecond
+		switch args[i] {
+		case "depth":
+			l.Depth = v
+		case "nodes":
+			l.Nodes = v
+		case "movetime":
+			l.MoveTime = ms
+		case "wtime":
+			l.WhiteTime = ms
+		case "btime":
+			l.BlackTime = ms
+		case "winc":
+			l.WhiteInc = ms
+		case "binc":
+			l.BlackInc = ms
+		case "movestogo":
+			l.MovesToGo = v
+		case "mate":
+			l.Depth = 2*v - 1
+		default:
+			return l, fmt.Errorf("unknown go argument %s", args[i])
+		}
+		i++
+	}
+	return l, nil
+}
+
+// searchLimits returns the limits of the search for the side to move.
+func (l Limits) searchLimits(whitesTurn bool, ponderHit <-chan struct{}) search.Limits {
+	sl := search.Limits{Depth: l.Depth, Nodes: l.Nodes, MovesToGo: l.MovesToGo}
+	if l.Infinite {
+		return sl
+	}
+	sl.MoveTime, sl.Time, sl.Inc = l.MoveTime, l.WhiteTime, l.WhiteInc
+	if !whitesTurn {
+		sl.Time, sl.Inc = l.BlackTime, l.BlackInc
+	}
+	if l.Ponder {
+		sl.PonderHit = ponderHit
+	}
+	return sl
+}
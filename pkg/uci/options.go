@@ -0,0 +1,84 @@
+package uci
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Option is a setting the GUI can change with setoption.
+type Option struct {
+	Name    string
+	Type    string // check, spin, combo, button or string
+	Default string
+	Min     int
+	Max     int
+	Vars    []string // Values of a combo
+	Set     func(value string) error
+}
+
+// String returns the option as sent in response to uci.
+func (o *Option) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "option name %s type %s", o.Name, o.Type)
+	if o.Type != "button" {
+		fmt.Fprintf(&sb, " default %s", o.Default)
+	}
+	if o.Type == "spin" {
+		fmt.Fprintf(&sb, " min %d max %d", o.Min, o.Max)
+	}
+	for _, v := range o.Vars {
+		fmt.Fprintf(&sb, " var %s", v)
+	}
+	return sb.String()
+}
+
+// AddOption makes the option available to the GUI.
+func (e *Engine) AddOption(o *Option) {
+	e.options = append(e.options, o)
+}
+
+// setOption handles: setoption name <id> [value <x>]
+func (e *Engine) setOption(args []string) error {
+	// Names and values can contain spaces
+	var name, value []string
+	var target *[]string
+	for _, arg := range args {
+		switch arg {
+		case "name":
+			target = &name
+		case "value":
+			target = &value
+		default:
+			if target == nil {
+				return fmt.Errorf("missing option name")
+			}
+			*target = append(*target, arg)
+		}
+	}
+	nameStr, valueStr := strings.Join(name, " "), strings.Join(value, " ")
+
+	for _, o := range e.options {
+		if !strings.EqualFold(o.Name, nameStr) {
+			continue
+		}
+
+		// Check the value
+		switch o.Type {
+		case "check":
+			if valueStr != "true" && valueStr != "false" {
+				return fmt.Errorf("invalid value %q of option %s", valueStr, o.Name)
+			}
+		case "spin":
+			v, err := strconv.Atoi(valueStr)
+			if err != nil || v < o.Min || v > o.Max {
+				return fmt.Errorf("invalid value %q of option %s", valueStr, o.Name)
+			}
+		}
+		if o.Set == nil {
+			return nil
+		}
+		return o.Set(valueStr)
+	}
+	return fmt.Errorf("unknown option %s", nameStr)
+}
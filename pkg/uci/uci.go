@@ -0,0 +1,331 @@
+package uci
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gochess/pkg/evaluation"
+	"gochess/pkg/generation"
+	"gochess/pkg/notation/move"
+	"gochess/pkg/notation/position"
+	"gochess/pkg/search"
+)
+
+const (
+	engineName   = "gochess"
+	engineAuthor = "axbudke"
+)
+
+// Engine speaks the Universal Chess Interface, reading commands and writing responses.
+type Engine struct {
+	in       io.Reader
+	out      io.Writer
+	outMutex sync.Mutex
+
+	position *position.Position
+	searcher *search.Searcher
+	options  []*Option
+	debug    bool // Report the position set, for the GUI to check its moves
+
+	// Running search
+	cancel    context.CancelFunc
+	done      chan struct{}
+	infinite  bool          // The best move waits for stop, or ponderhit when pondering
+	ponderHit chan struct{} // Closed at ponderhit
+}
+
+func NewEngine(in io.Reader, out io.Writer) *Engine {
+	p, _ := position.NewPosition(position.StartingFEN)
+	e := &Engine{
+		in:       in,
+		out:      out,
+		position: p,
+		searcher: search.NewSearcher(),
+	}
+	e.searcher.OnInfo = e.sendInfo
+	e.AddOption(&Option{
+		Name:    "Hash",
+		Type:    "spin",
+		Default: strconv.Itoa(search.DefaultHashMB),
+		Min:     1,
+		Max:     search.MaxHashMB,
+		Set: func(value string) error {
+			sizeMB, _ := strconv.Atoi(value)
+			e.searcher.TT.Resize(sizeMB)
+			return nil
+		},
+	})
+	e.AddOption(&Option{
+		Name:    "Threads",
+		Type:    "spin",
+		Default: "1",
+		Min:     1,
+		Max:     search.MaxThreads,
+		Set: func(value string) error {
+			e.searcher.Threads, _ = strconv.Atoi(value)
+			return nil
+		},
+	})
+
+	e.AddOption(&Option{
+		Name:    "Move Overhead",
+		Type:    "spin",
+		Default: strconv.Itoa(int(search.DefaultMoveOverhead.Milliseconds())),
+		Min:     0,
+		Max:     int(search.MaxMoveOverhead.Milliseconds()),
+		Set: func(value string) error {
+			ms, _ := strconv.Atoi(value)
+			e.searcher.MoveOverhead = time.Duration(ms) * time.Millisecond
+			return nil
+		},
+	})
+	e.AddOption(&Option{Name: "Ponder", Type: "check", Default: "false"})
+
+	e.AddOption(&Option{
+		Name:    "EvalFile",
+		Type:    "string",
+		Default: "<empty>",
+		Set: func(value string) error {
+			if value == "" || value == "<empty>" {
+				e.searcher.Evaluator.SetParams(&evaluation.DefaultParams)
+				return nil
+			}
+			params, err := evaluation.LoadParams(value)
+			if err != nil {
+				return err
+			}
+			e.searcher.Evaluator.SetParams(params)
+			return nil
+		},
+	})
+
+	// Switches of the selective search techniques, for matches between versions
+	for _, o := range []struct {
+		name  string
+		value *bool
+	}{
+		{"NullMove", &e.searcher.Options.NullMove},
+		{"LateMoveReductions", &e.searcher.Options.LateMoveReductions},
+		{"ReverseFutility", &e.searcher.Options.ReverseFutility},
+		{"Futility", &e.searcher.Options.Futility},
+		{"CheckExtensions", &e.searcher.Options.CheckExtensions},
+	} {
+		o := o
+		e.AddOption(&Option{
+			Name:    o.name,
+			Type:    "check",
+			Default: strconv.FormatBool(*o.value),
+			Set: func(value string) error {
+				*o.value = value == "true"
+				return nil
+			},
+		})
+	}
+
+	// Switches of the evaluation terms, to measure what they are worth
+	for _, term := range evaluation.Terms {
+		term := term
+		e.AddOption(&Option{
+			Name:    "Eval" + term.String(),
+			Type:    "check",
+			Default: "true",
+			Set: func(value string) error {
+				if value == "true" {
+					e.searcher.Evaluator.Terms |= term
+				} else {
+					e.searcher.Evaluator.Terms &^= term
+				}
+				return nil
+			},
+		})
+	}
+	return e
+}
+
+// sendInfo reports a completed iteration of the search.
+func (e *Engine) sendInfo(info search.Info) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "info depth %d score %s nodes %d", info.Depth, info.Score, info.Nodes)
+	if ms := info.Time.Milliseconds(); ms > 0 {
+		fmt.Fprintf(&sb, " nps %d", int64(info.Nodes)*1000/ms)
+	}
+	fmt.Fprintf(&sb, " hashfull %d time %d pv", info.Hashfull, info.Time.Milliseconds())
+	for _, m := range info.PV {
+		fmt.Fprintf(&sb, " %s", m.PCN())
+	}
+	e.send("%s", sb.String())
+}
+
+// send writes a line to the GUI, it is safe to call while searching.
+func (e *Engine) send(format string, args ...any) {
+	e.outMutex.Lock()
+	defer e.outMutex.Unlock()
+	fmt.Fprintf(e.out, format+"\n", args...)
+}
+
+// Run handles commands until quit or the end of the input.
+func (e *Engine) Run() error {
+	scanner := bufio.NewScanner(e.in)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch cmd, args := fields[0], fields[1:]; cmd {
+		case "uci":
+			e.send("id name %s", engineName)
+			e.send("id author %s", engineAuthor)
+			for _, o := range e.options {
+				e.send("%s", o)
+			}
+			e.send("uciok")
+		case "debug":
+			e.debug = len(args) > 0 && args[0] == "on"
+		case "isready":
+			e.send("readyok")
+		case "setoption":
+			e.stop()
+			if err := e.setOption(args); err != nil {
+				e.info("%v", err)
+			}
+		case "ucinewgame":
+			e.stop()
+			e.position, _ = position.NewPosition(position.StartingFEN)
+			e.searcher.Clear()
+		case "position":
+			e.stop()
+			if err := e.setPosition(args); err != nil {
+				e.info("%v", err)
+			} else if e.debug {
+				e.info("position %s", e.position.FEN())
+			}
+		case "go":
+			e.stop()
+			limits, err := parseLimits(args)
+			if err != nil {
+				e.info("%v", err)
+				continue
+			}
+			e.start(limits)
+		case "stop":
+			e.stop()
+		case "ponderhit":
+			if e.ponderHit != nil {
+				close(e.ponderHit)
+				e.ponderHit, e.infinite = nil, false
+			}
+		case "register":
+		case "quit":
+			e.stop()
+			return nil
+		default:
+			e.info("unknown command %s", cmd)
+		}
+	}
+
+	// Piped commands end without quit, let the search finish
+	if e.done != nil && !e.infinite {
+		<-e.done
+	}
+	e.stop()
+	return scanner.Err()
+}
+
+// info sends a message for the user of the GUI.
+func (e *Engine) info(format string, args ...any) {
+	e.send("info string "+format, args...)
+}
+
+// setPosition handles: position [startpos | fen <fen>] [moves <move>...]
+func (e *Engine) setPosition(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing position")
+	}
+
+	// Find the starting position
+	var fen position.FEN
+	i := 1
+	switch args[0] {
+	case "startpos":
+		fen = position.StartingFEN
+	case "fen":
+		for i < len(args) && args[i] != "moves" {
+			i++
+		}
+		fen = position.FEN(strings.Join(args[1:i], " "))
+	default:
+		return fmt.Errorf("invalid position %s", args[0])
+	}
+	p, err := position.NewPosition(fen)
+	if err != nil {
+		return fmt.Errorf("invalid fen %q: %w", fen, err)
+	}
+
+	// Play the moves
+	if i < len(args) && args[i] == "moves" {
+		for _, pcn := range args[i+1:] {
+			m, err := move.NewMoveFromPCN(p, move.PCN(pcn))
+			if err != nil {
+				return err
+			}
+			p = generation.MakeMove(p, m)
+		}
+	}
+	e.position = p
+	return nil
+}
+
+// start searches the current position in the background until the limits are reached or stop.
+func (e *Engine) start(limits Limits) {
+	var ctx context.Context
+	ctx, e.cancel = context.WithCancel(context.Background())
+	done, ponderHit := make(chan struct{}), make(chan struct{})
+	e.done, e.infinite = done, limits.Infinite || limits.Ponder
+	if limits.Ponder {
+		e.ponderHit = ponderHit
+	}
+
+	p := e.position.Copy()
+	go func() {
+		defer close(done)
+		result := e.searcher.Search(ctx, p, limits.searchLimits(p.WhitesTurn, ponderHit))
+
+		// In infinite mode the best move waits for stop, when pondering for ponderhit too
+		if limits.Infinite {
+			<-ctx.Done()
+		} else if limits.Ponder {
+			select {
+			case <-ctx.Done():
+			case <-ponderHit:
+			}
+		}
+		if result.Move == nil {
+			e.send("bestmove 0000")
+			return
+		}
+
+		// Suggest pondering on the reply expected
+		if len(result.PV) > 1 {
+			e.send("bestmove %s ponder %s", result.Move.PCN(), result.PV[1].PCN())
+			return
+		}
+		e.send("bestmove %s", result.Move.PCN())
+	}()
+}
+
+// stop ends the running search, if any, once its best move is sent.
+func (e *Engine) stop() {
+	if e.cancel == nil {
+		return
+	}
+	e.cancel()
+	<-e.done
+	e.cancel, e.done, e.ponderHit = nil, nil, nil
+}
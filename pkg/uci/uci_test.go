@@ -0,0 +1,147 @@
+package uci_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"gochess/pkg/uci"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// run sends the commands to a new engine and returns its responses.
+func run(t *testing.T, commands string, options ...*uci.Option) []string {
+	var out bytes.Buffer
+	e := uci.NewEngine(strings.NewReader(commands), &out)
+	for _, o := range options {
+		e.AddOption(o)
+	}
+	require.NoError(t, e.Run())
+	return strings.Split(strings.TrimSpace(out.String()), "\n")
+}
+
+func TestEngineHandshake(t *testing.T) {
+	style := &uci.Option{Name: "Style", Type: "combo", Default: "Normal", Vars: []string{"Solid", "Normal", "Risky"}}
+	lines := run(t, "uci\nisready\nquit\n", style)
+	assert.Equal(t, []string{
+		"id name gochess",
+		"id author axbudke",
+		"option name Hash type spin default 16 min 1 max 4096",
+		"option name Threads type spin default 1 min 1 max 256",
+		"option name Move Overhead type spin default 30 min 0 max 5000",
+		"option name Ponder type check default false",
+		"option name EvalFile type string default <empty>",
+		"option name NullMove type check default true",
+		"option name LateMoveReductions type check default true",
+		"option name ReverseFutility type check default true",
+		"option name Futility type check default true",
+		"option name CheckExtensions type check default true",
+		"option name EvalPawnStructure type check default true",
+		"option name EvalMobility type check default true",
+		"option name EvalPawnShield type check default true",
+		"option name EvalKingFiles type check default true",
+		"option name EvalKingAttacks type check default true",
+		"option name Style type combo default Normal var Solid var Normal var Risky",
+		"uciok",
+		"readyok",
+	}, lines)
+}
+
+func TestEngineGo(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands string
+		expected string
+	}{
+		{"Mate In One", "position fen 6k1/5ppp/8/8/8/8/8/R3K3 w - - 0 1\ngo depth 1\n", "bestmove a1a8"},
+		{"Capture", "position fen 4k3/8/8/3q4/8/8/8/3RK3 w - - 0 1\ngo depth 1\n", "bestmove d1d5"},
+		{"Moves From FEN", "position fen 6k1/5ppp/8/8/8/8/8/R3K3 b - - 0 1 moves g8f8 a1b1 f8g8\ngo wtime 1000 btime 1000\n", "bestmove b1b8"},
+		{"Infinite", "position startpos\ngo infinite\nstop\n", "bestmove "},
+		{"No Moves", "position fen 7k/5Q2/6K1/8/8/8/8/8 b - - 0 1\ngo\n", "bestmove 0000"},
+		{"Pruning Switched Off", "setoption name NullMove value false\nsetoption name Futility value false\n" +
+			"position fen 4k3/8/8/3q4/8/8/8/3RK3 w - - 0 1\ngo depth 4\n", "bestmove d1d5"},
+		{"Threads", "setoption name Threads value 4\nposition fen 6k1/5ppp/8/8/8/8/8/R3K3 w - - 0 1\ngo depth 4\n", "bestmove a1a8"},
+		{"Terms Switched Off", "setoption name EvalMobility value false\nsetoption name EvalKingAttacks value false\n" +
+			"position fen 6k1/5ppp/8/8/8/8/8/R3K3 w - - 0 1\ngo depth 2\n", "bestmove a1a8"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lines := run(t, test.commands)
+			require.NotEmpty(t, lines)
+			assert.Contains(t, lines[len(lines)-1], test.expected)
+		})
+	}
+}
+
+func TestEngineTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands string
+		min, max time.Duration
+		expected string
+	}{
+		{"Move Time", "position startpos\ngo movetime 200\n", 150 * time.Millisecond, time.Second, "bestmove "},
+		{"Clock", "position startpos\ngo wtime 3000 btime 3000 winc 100 binc 100\n", 0, time.Second, "bestmove "},
+		{"Moves To Go", "position startpos moves e2e4\ngo wtime 500 btime 500 movestogo 2\n", 0, 500 * time.Millisecond, "bestmove "},
+		{"Single Move", "position fen k7/8/8/8/8/8/1q6/K7 w - - 0 1\ngo wtime 60000 btime 60000\n", 0, 500 * time.Millisecond, "bestmove a1b2"},
+		{"Ponder Hit", "position startpos\ngo ponder wtime 1000 btime 1000\nponderhit\n", 0, time.Second, "bestmove "},
+		{"Ponder Stop", "position startpos\ngo ponder wtime 60000 btime 60000\nstop\n", 0, time.Second, "bestmove "},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start := time.Now()
+			lines := run(t, test.commands)
+			elapsed := time.Since(start)
+			require.NotEmpty(t, lines)
+			assert.Contains(t, lines[len(lines)-1], test.expected)
+			assert.GreaterOrEqual(t, elapsed, test.min)
+			assert.Less(t, elapsed, test.max)
+		})
+	}
+}
+
+func TestEngineDebug(t *testing.T) {
+	lines := run(t, "position startpos moves e2e4\ndebug on\nposition startpos moves e2e4 c7c5\ndebug off\nposition startpos\n")
+	assert.Equal(t, []string{
+		"info string position rnbqkbnr/pp1ppppp/8/2p5/4P3/8/PPPP1PPP/RNBQKBNR w KQkq c6 0 2",
+	}, lines)
+}
+
+func TestEngineErrors(t *testing.T) {
+	var set string
+	ownBook := &uci.Option{Name: "OwnBook", Type: "check", Default: "false", Set: func(value string) error {
+		set = value
+		return nil
+	}}
+	lines := run(t, strings.Join([]string{
+		"position startpos moves e2e5",
+		"position fen 8/8/8/8/8/8/8 w - - 0 1",
+		"go depth x",
+		"setoption name MultiPV value 2",
+		"setoption name Threads value 0",
+		"setoption name OwnBook value maybe",
+		"setoption name OwnBook value true",
+		"setoption name Move Overhead value -1",
+		"setoption name Hash value 0",
+		"setoption name hash value 1",
+		"setoption name EvalFile value /missing.json",
+		"setoption name EvalFile value <empty>",
+		"flip",
+	}, "\n"), ownBook)
+	assert.Equal(t, []string{
+		"info string illegal move e2e5: P on e2 can't move to e5",
+		`info string invalid fen "8/8/8/8/8/8/8 w - - 0 1": failed to parse regexp`,
+		`info string invalid value of depth: strconv.Atoi: parsing "x": invalid syntax`,
+		"info string unknown option MultiPV",
+		`info string invalid value "0" of option Threads`,
+		`info string invalid value "maybe" of option OwnBook`,
+		`info string invalid value "-1" of option Move Overhead`,
+		`info string invalid value "0" of option Hash`,
+		"info string open /missing.json: no such file or directory",
+		"info string unknown command flip",
+	}, lines)
+	assert.Equal(t, "true", set)
+}